@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v3"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	grpccodes "google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/client"
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+	"github.com/longhorn/longhorn-share-manager/pkg/rpc"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+
+	// the share manager name reported by the health command, the gRPC health service has no name for it
+	shareManagerHealthService = "share-manager"
+)
+
+// clientFlags are shared by the commands talking to a running share manager
+func clientFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "address of the share manager gRPC server",
+			Value: "localhost" + listenPort,
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "output format, table or json",
+			Value:   outputTable,
+			Validator: func(output string) error {
+				if output != outputTable && output != outputJSON {
+					return fmt.Errorf("unknown output format %q, expected %v or %v", output, outputTable, outputJSON)
+				}
+				return nil
+			},
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "timeout of the command including retries, 0 for the default timeout of each call",
+		},
+		&cli.StringFlag{
+			Name:  "tls-ca",
+			Usage: "CA bundle to verify the server certificate, enables TLS",
+		},
+		&cli.StringFlag{
+			Name:  "tls-cert",
+			Usage: "client certificate for mutual TLS",
+		},
+		&cli.StringFlag{
+			Name:  "tls-key",
+			Usage: "private key of the client certificate",
+		},
+		&cli.StringFlag{
+			Name:  "tls-server-name",
+			Usage: "name verified against the server certificate instead of the host of the address",
+		},
+		&cli.StringFlag{
+			Name:  "token-file",
+			Usage: "file containing a bearer token to authenticate with, requires TLS",
+		},
+	}
+}
+
+// newClient connects to the share manager and returns the context for the command
+func newClient(ctx context.Context, c *cli.Command) (*client.ShareManagerClient, context.Context, context.CancelFunc, error) {
+	opts := []client.ClientOption{}
+	if c.IsSet("tls-ca") {
+		opts = append(opts, client.WithTLS(c.String("tls-ca")))
+	}
+	if c.IsSet("tls-cert") || c.IsSet("tls-key") {
+		opts = append(opts, client.WithClientCertificate(c.String("tls-cert"), c.String("tls-key")))
+	}
+	if c.IsSet("tls-server-name") {
+		opts = append(opts, client.WithServerName(c.String("tls-server-name")))
+	}
+	if c.IsSet("token-file") {
+		opts = append(opts, client.WithBearerToken(c.String("token-file")))
+	}
+
+	smClient, err := client.NewShareManagerClient(c.String("address"), opts...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	if timeout := c.Duration("timeout"); timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		return smClient, ctx, func() { cancelTimeout(); cancel() }, nil
+	}
+	return smClient, ctx, cancel, nil
+}
+
+// clientCommand wraps an action which needs a connected client
+func clientCommand(name, usage string, flags []cli.Flag, action func(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient) error) *cli.Command {
+	return &cli.Command{
+		Name:  name,
+		Usage: usage,
+		Flags: append(clientFlags(), flags...),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			smClient, ctx, cancel, err := newClient(ctx, c)
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			defer func() {
+				_ = smClient.Close()
+			}()
+
+			return action(ctx, c, smClient)
+		},
+	}
+}
+
+// printOutput writes v as JSON or calls table to write it as a table
+func printOutput(c *cli.Command, v any, table func(w io.Writer)) error {
+	if c.String("output") == outputJSON {
+		if m, ok := v.(proto.Message); ok {
+			out, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(m)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Println(string(out))
+			return err
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func StatusCmd() *cli.Command {
+	return clientCommand("status", "show the state of the volume and nfs server", nil,
+		func(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient) error {
+			status, err := smClient.GetStatus(ctx)
+			if err != nil {
+				return err
+			}
+
+			return printOutput(c, status, func(w io.Writer) {
+				fmt.Fprintf(w, "VOLUME\t%v\n", status.Volume)
+				fmt.Fprintf(w, "DATA ENGINE\t%v\n", status.DataEngine)
+				fmt.Fprintf(w, "FILESYSTEM\t%v\n", status.FsType)
+				fmt.Fprintf(w, "ENCRYPTED\t%v\n", status.Encrypted)
+				fmt.Fprintf(w, "DEVICE\t%v\n", status.DevicePath)
+				fmt.Fprintf(w, "MOUNT PATH\t%v\n", status.MountPath)
+				fmt.Fprintf(w, "MOUNTED\t%v\n", status.Mounted)
+				fmt.Fprintf(w, "EXPORTED\t%v\n", status.Exported)
+				fmt.Fprintf(w, "NFS SERVER RUNNING\t%v\n", status.NfsServerRunning)
+				fmt.Fprintf(w, "SERVING\t%v\n", status.Serving)
+				fmt.Fprintf(w, "INTEGRITY PROTECTED\t%v\n", status.IntegrityProtected)
+				fmt.Fprintf(w, "INTEGRITY ERRORS\t%v\n", status.IntegrityErrors)
+				fmt.Fprintf(w, "PROJECT QUOTA\t%v\n", status.ProjectQuota)
+				for _, port := range status.Ports {
+					fmt.Fprintf(w, "PORT %v\t%v\n", strings.ToUpper(port.Service), port.Port)
+				}
+			})
+		})
+}
+
+type healthStatus struct {
+	Service string `json:"service"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+func HealthCmd() *cli.Command {
+	return clientCommand("health", "check the health of the share manager and the data integrity of the volume", nil,
+		func(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient) error {
+			statuses := []healthStatus{}
+			for _, service := range []string{"", rpc.IntegrityHealthService} {
+				status, err := smClient.CheckHealth(ctx, service)
+				if service == rpc.IntegrityHealthService && grpcstatus.Code(err) == grpccodes.NotFound {
+					// the volume has no integrity protection
+					continue
+				}
+
+				health := healthStatus{Service: service, Status: status.String()}
+				if service == "" {
+					health.Service = shareManagerHealthService
+				}
+				if err != nil {
+					health.Status = healthpb.HealthCheckResponse_NOT_SERVING.String()
+					health.Error = grpcstatus.Convert(err).Message()
+				}
+				statuses = append(statuses, health)
+			}
+
+			if err := printOutput(c, statuses, func(w io.Writer) {
+				fmt.Fprintln(w, "SERVICE\tSTATUS\tERROR")
+				for _, health := range statuses {
+					fmt.Fprintf(w, "%v\t%v\t%v\n", health.Service, health.Status, health.Error)
+				}
+			}); err != nil {
+				return err
+			}
+
+			// the exit code tells scripts and probes about a service which is not serving
+			for _, health := range statuses {
+				if health.Status != healthpb.HealthCheckResponse_SERVING.String() {
+					return fmt.Errorf("%v is %v", health.Service, health.Status)
+				}
+			}
+			return nil
+		})
+}
+
+func MountCmd() *cli.Command {
+	return clientCommand("mount", "mount and export the volume", nil,
+		func(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient) error {
+			return smClient.MountWithContext(ctx)
+		})
+}
+
+func UnmountCmd() *cli.Command {
+	return clientCommand("unmount", "unexport and unmount the volume, cancelling running operations", nil,
+		func(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient) error {
+			return smClient.UnmountWithContext(ctx)
+		})
+}
+
+func TrimCmd() *cli.Command {
+	return clientCommand("trim", "trim the mounted filesystem",
+		append(operationFlags(), &cli.BoolFlag{
+			Name:  "encrypted-device",
+			Usage: "trim through the crypto device of an encrypted volume",
+		}),
+		func(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient) error {
+			op, err := smClient.StartFilesystemTrimWithContext(ctx, c.Bool("encrypted-device"))
+			if err != nil {
+				return err
+			}
+			return followOperation(ctx, c, smClient, op)
+		})
+}
+
+func ResizeCmd() *cli.Command {
+	return clientCommand("resize", "grow the crypto device and filesystem to the size of the volume", operationFlags(),
+		func(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient) error {
+			op, err := smClient.StartFilesystemResizeWithContext(ctx)
+			if err != nil {
+				return err
+			}
+			return followOperation(ctx, c, smClient, op)
+		})
+}
+
+func ClientsCmd() *cli.Command {
+	return clientCommand("clients", "list the nfs clients connected to the share manager", nil,
+		func(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient) error {
+			clients, err := smClient.ListClients(ctx)
+			if err != nil {
+				return err
+			}
+
+			return printOutput(c, clients, func(w io.Writer) {
+				fmt.Fprintln(w, "ADDRESS\tCONNECTIONS")
+				for _, nfsClient := range clients {
+					fmt.Fprintf(w, "%v\t%v\n", nfsClient.Address, nfsClient.Connections)
+				}
+			})
+		})
+}
+
+func operationFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "no-wait",
+			Usage: "return once the operation started instead of waiting for it to finish",
+		},
+	}
+}
+
+// followOperation waits for the operation unless --no-wait is set and prints its final state.
+// Interrupting the command stops waiting, but the operation keeps running.
+func followOperation(ctx context.Context, c *cli.Command, smClient *client.ShareManagerClient, op *smextrpc.Operation) error {
+	if !c.Bool("no-wait") {
+		final, err := smClient.WatchOperation(ctx, op.Id, func(update *smextrpc.Operation) {
+			if c.String("output") == outputTable && update.Message != "" && !types.IsOperationDone(update.State) {
+				fmt.Fprintf(os.Stderr, "%v: %v\n", update.State, update.Message)
+			}
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("stopped waiting, %v operation %v keeps running: %w", op.Type, op.Id, err)
+			}
+			return err
+		}
+		op = final
+	}
+
+	if err := printOutput(c, op, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tTYPE\tSTATE\tDURATION\tMESSAGE\tERROR")
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\n", op.Id, op.Type, op.State, operationDuration(op), op.Message, op.Error)
+	}); err != nil {
+		return err
+	}
+
+	if op.State == types.OperationStateFailed || op.State == types.OperationStateCancelled {
+		return fmt.Errorf("%v operation %v %v", op.Type, op.Id, op.State)
+	}
+	return nil
+}
+
+func operationDuration(op *smextrpc.Operation) time.Duration {
+	end := time.Now()
+	if op.EndTime != nil {
+		end = op.EndTime.AsTime()
+	}
+	return end.Sub(op.StartTime.AsTime()).Round(time.Second)
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/server"
+	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+func RenderConfigCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "render-config",
+		Usage: "print the nfs server config for the given volumes without starting anything",
+		Flags: append([]cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     "volume",
+				Usage:    "volume to export, can be repeated",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "export-path",
+				Usage: "directory the volumes are mounted below",
+				Value: types.ExportPath,
+			},
+			&cli.StringFlag{
+				Name:  "access-type",
+				Usage: "access type of the volume exports: RW, RO or None",
+				Value: nfs.DefaultExportOptions.AccessType,
+			},
+			&cli.StringFlag{
+				Name:  "squash",
+				Usage: "squash of the volume exports: None, Root, RootId or All",
+				Value: nfs.DefaultExportOptions.Squash,
+			},
+			&cli.StringSliceFlag{
+				Name:  "clients",
+				Usage: "host, network or netgroup with the access type, can be repeated, all clients get the access type if unset",
+			},
+			&cli.IntFlag{
+				Name:    "lease-lifetime",
+				Usage:   "NFSv4 lease lifetime in seconds",
+				Value:   server.DefaultLeaseLifetime,
+				Sources: cli.EnvVars(server.EnvKeyLeaseLifetime),
+			},
+			&cli.IntFlag{
+				Name:    "grace-period",
+				Usage:   "NFSv4 grace period in seconds",
+				Value:   server.DefaultGracePeriod,
+				Sources: cli.EnvVars(server.EnvKeyGracePeriod),
+			},
+			&cli.BoolFlag{
+				Name:    "enable-nfsv3",
+				Usage:   "serve NFSv3 with NLM locking and RQUOTA next to NFSv4",
+				Sources: cli.EnvVars(server.EnvKeyEnableNFSv3),
+			},
+			&cli.IntFlag{
+				Name:    "mount-port",
+				Usage:   "port of the NFSv3 MOUNT service",
+				Value:   nfs.DefaultMountPort,
+				Sources: cli.EnvVars(server.EnvKeyMountPort),
+			},
+			&cli.IntFlag{
+				Name:    "nlm-port",
+				Usage:   "port of the NFSv3 NLM locking service",
+				Value:   nfs.DefaultNLMPort,
+				Sources: cli.EnvVars(server.EnvKeyNLMPort),
+			},
+			&cli.IntFlag{
+				Name:    "rquota-port",
+				Usage:   "port of the NFSv3 RQUOTA service",
+				Value:   nfs.DefaultRQUOTAPort,
+				Sources: cli.EnvVars(server.EnvKeyRQUOTAPort),
+			},
+			&cli.StringFlag{
+				Name:      "recovery-backend",
+				Usage:     "where the NFSv4 client recovery records are kept: longhorn or fs",
+				Value:     nfs.RecoveryBackendLonghorn,
+				Sources:   cli.EnvVars(server.EnvKeyRecoveryBackend),
+				Validator: nfs.ValidateRecoveryBackend,
+			},
+			&cli.StringFlag{
+				Name:  "recovery-root",
+				Usage: "directory of the records of the fs recovery backend, defaults to the state directory on the first volume",
+			},
+		}, tuningFlags()...),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			recoveryRoot := c.String("recovery-root")
+			if c.String("recovery-backend") == nfs.RecoveryBackendFS && recoveryRoot == "" {
+				recoveryRoot = types.GetRecoveryPath(c.StringSlice("volume")[0])
+			}
+
+			exportOptions := nfs.ExportOptions{
+				AccessType: c.String("access-type"),
+				Squash:     c.String("squash"),
+				Clients:    c.StringSlice("clients"),
+			}
+			config, err := nfs.RenderConfig(c.String("export-path"), c.StringSlice("volume"), exportOptions, nfs.ConfigOptions{
+				LeaseLifetime:   c.Int("lease-lifetime"),
+				GracePeriod:     c.Int("grace-period"),
+				EnableNFSv3:     c.Bool("enable-nfsv3"),
+				MountPort:       c.Int("mount-port"),
+				NLMPort:         c.Int("nlm-port"),
+				RQUOTAPort:      c.Int("rquota-port"),
+				Tuning:          nfs.DeriveTuning(tuningFromFlags(c)),
+				RecoveryBackend: c.String("recovery-backend"),
+				RecoveryRoot:    recoveryRoot,
+			})
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(config)
+			return err
+		},
+	}
+}
+
+func ValidateConfigCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "validate-config",
+		Usage:     "check an nfs server config for duplicate export ids, pseudo paths and filesystem ids and for exports without FSAL",
+		ArgsUsage: "<config file>",
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() != 1 {
+				return fmt.Errorf("expected exactly one config file, got %v arguments", c.Args().Len())
+			}
+			path := c.Args().First()
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			problems, err := nfs.ValidateConfig(content)
+			if err != nil {
+				return fmt.Errorf("failed to parse %v: %w", path, err)
+			}
+			for _, problem := range problems {
+				fmt.Printf("%v:%d: %v\n", path, problem.Line, problem.Message)
+			}
+			if len(problems) > 0 {
+				return fmt.Errorf("found %v problems in %v", len(problems), path)
+			}
+			return nil
+		},
+	}
+}
+
+// tuningFlags are the nfs server tuning flags shared by the server and render-config
+func tuningFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:    "worker-threads",
+			Usage:   "number of ganesha worker threads, derived from the CPU limit if unset, at least the ganesha default of 256",
+			Sources: cli.EnvVars(server.EnvKeyWorkerThreads),
+		},
+		&cli.IntFlag{
+			Name:    "max-connections",
+			Usage:   "maximum number of RPC connections",
+			Sources: cli.EnvVars(server.EnvKeyMaxConnections),
+		},
+		&cli.IntFlag{
+			Name:    "max-requests",
+			Usage:   "maximum number of RPC requests in flight",
+			Sources: cli.EnvVars(server.EnvKeyMaxRequests),
+		},
+		&cli.IntFlag{
+			Name:    "cache-entries",
+			Usage:   "number of files and directories in the metadata cache, derived from the memory limit if unset, at least the ganesha default of 100000",
+			Sources: cli.EnvVars(server.EnvKeyCacheEntries),
+		},
+		&cli.IntFlag{
+			Name:    "cache-chunks",
+			Usage:   "number of directory chunks in the metadata cache",
+			Sources: cli.EnvVars(server.EnvKeyCacheChunks),
+		},
+		&cli.IntFlag{
+			Name:    "dir-chunk",
+			Usage:   "number of directory entries per chunk of the metadata cache",
+			Sources: cli.EnvVars(server.EnvKeyDirChunk),
+		},
+	}
+}
+
+func tuningFromFlags(c *cli.Command) nfs.Tuning {
+	return nfs.Tuning{
+		WorkerThreads:  c.Int("worker-threads"),
+		MaxConnections: c.Int("max-connections"),
+		MaxRequests:    c.Int("max-requests"),
+		CacheEntries:   c.Int("cache-entries"),
+		CacheChunks:    c.Int("cache-chunks"),
+		DirChunk:       c.Int("dir-chunk"),
+	}
+}
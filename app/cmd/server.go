@@ -6,18 +6,29 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/longhorn/types/pkg/generated/smrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/longhorn/longhorn-share-manager/pkg/audit"
+	"github.com/longhorn/longhorn-share-manager/pkg/crypto"
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
 	"github.com/longhorn/longhorn-share-manager/pkg/rpc"
 	"github.com/longhorn/longhorn-share-manager/pkg/server"
+	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
 	"github.com/longhorn/longhorn-share-manager/pkg/types"
 	"github.com/longhorn/longhorn-share-manager/pkg/util"
 	"github.com/longhorn/longhorn-share-manager/pkg/volume"
@@ -25,12 +36,18 @@ import (
 
 const (
 	listenPort = ":9600"
+
+	tracingShutdownTimeout = 5 * time.Second
+
+	// defaultDrainTimeout leaves time for stopping the nfs server and unmounting
+	// within the default termination grace period of 30 seconds
+	defaultDrainTimeout = 15 * time.Second
 )
 
 func ServerCmd() *cli.Command {
 	return &cli.Command{
 		Name: "daemon",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "volume",
 				Usage:    "The volume to export via the nfs server",
@@ -89,9 +106,27 @@ func ServerCmd() *cli.Command {
 				Sources:  cli.EnvVars("CRYPTOPBKDFMEMORY"),
 				Required: false,
 			},
+			&cli.StringFlag{
+				Name:     "crypto-integrity",
+				Usage:    "enables LUKS2 authenticated encryption with the given dm-integrity mode (hmac-sha256, hmac-sha512, poly1305 or aead)",
+				Sources:  cli.EnvVars("CRYPTOINTEGRITY"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "crypto-header",
+				Usage:    "absolute path of a detached LUKS header file, it has to be reachable from every node the volume can attach to, e.g. on a shared host path",
+				Sources:  cli.EnvVars("CRYPTOHEADER"),
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "crypto-header-init",
+				Usage:    "create the detached LUKS header and encrypt the volume if the header does not exist, only set this for a new volume",
+				Sources:  cli.EnvVars("CRYPTOHEADERINIT"),
+				Required: false,
+			},
 			&cli.StringFlag{
 				Name:     "fs",
-				Usage:    "the filesystem to use for the volume",
+				Usage:    "the filesystem to use for the volume: ext4, xfs or btrfs",
 				Value:    "ext4",
 				Required: false,
 			},
@@ -100,8 +135,103 @@ func ServerCmd() *cli.Command {
 				Usage:    "allows for specifying additional mount options",
 				Required: false,
 			},
-		},
+			&cli.StringFlag{
+				Name:      "btrfs-subvolume",
+				Usage:     "subvolume of a btrfs filesystem to mount instead of the top level, created on first use",
+				Sources:   cli.EnvVars("BTRFS_SUBVOLUME"),
+				Validator: volume.ValidateBtrfsSubvolume,
+				Required:  false,
+			},
+			&cli.StringFlag{
+				Name:      "root-mode",
+				Usage:     "octal mode of the root directory of the volume",
+				Value:     fmt.Sprintf("%#o", volume.DefaultRootMode),
+				Sources:   cli.EnvVars("ROOT_MODE"),
+				Validator: validateRootMode,
+				Required:  false,
+			},
+			&cli.IntFlag{
+				Name:     "root-uid",
+				Usage:    "owner of the root directory of the volume, -1 keeps the current owner",
+				Value:    -1,
+				Sources:  cli.EnvVars("ROOT_UID"),
+				Required: false,
+			},
+			&cli.IntFlag{
+				Name:     "root-gid",
+				Usage:    "group of the root directory of the volume, -1 keeps the current group",
+				Value:    -1,
+				Sources:  cli.EnvVars("ROOT_GID"),
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "root-setgid",
+				Usage:    "sets the setgid bit on the root directory, so new files inherit its group like the fsGroup of a pod",
+				Sources:  cli.EnvVars("ROOT_SETGID"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:      "root-permissions",
+				Usage:     "when to set the root directory permissions: always on start, or preserve to only set them on a freshly formatted filesystem",
+				Value:     volume.RootPermissionsAlways,
+				Sources:   cli.EnvVars("ROOT_PERMISSIONS"),
+				Validator: volume.ValidateRootPermissionsPolicy,
+				Required:  false,
+			},
+			&cli.DurationFlag{
+				Name:     "drain-timeout",
+				Usage:    "how long to wait on shutdown for the outstanding nfs operations and the filesystem sync after the exports are removed, 0 disables draining",
+				Value:    defaultDrainTimeout,
+				Sources:  cli.EnvVars("DRAIN_TIMEOUT"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "tls-cert",
+				Usage:    "certificate file of the gRPC server, enables TLS, reloaded when the file changes",
+				Sources:  cli.EnvVars("TLS_CERT"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "tls-key",
+				Usage:    "private key file of the gRPC server certificate",
+				Sources:  cli.EnvVars("TLS_KEY"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "tls-client-ca",
+				Usage:    "CA bundle to verify client certificates, enables mutual TLS",
+				Sources:  cli.EnvVars("TLS_CLIENT_CA"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "audit-log",
+				Usage:    "destination of the JSON lines audit log of operations changing the share, '-' for stdout or a file path",
+				Sources:  cli.EnvVars("AUDIT_LOG"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "tracing-exporter",
+				Usage:    "exporter of OpenTelemetry trace spans: none, otlp (configured by the OTEL_EXPORTER_OTLP_* environment variables) or stdout",
+				Value:    tracing.ExporterNone,
+				Sources:  cli.EnvVars("TRACING_EXPORTER"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "authz-policy",
+				Usage:    "JSON policy mapping caller identities to the privileged methods they may call, enables authorization",
+				Sources:  cli.EnvVars("AUTHZ_POLICY"),
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "authz-token-file",
+				Usage:    "file with lines of '<identity> <token>' for callers authenticating with a bearer token, requires TLS",
+				Sources:  cli.EnvVars("AUTHZ_TOKEN_FILE"),
+				Required: false,
+			},
+		}, tuningFlags()...),
 		Action: func(ctx context.Context, c *cli.Command) error {
+			// the flag validator already rejected an invalid mode
+			rootMode, _ := volume.ParseRootMode(c.String("root-mode"))
 			vol := volume.Volume{
 				Name:                       c.String("volume"),
 				DataEngine:                 c.String("data-engine"),
@@ -112,31 +242,140 @@ func ServerCmd() *cli.Command {
 				CryptoPBKDF:                c.String("cryptopbkdf"),
 				CryptoPBKDFForceIterations: c.String("cryptopbkdfiterations"),
 				CryptoPBKDFMemory:          c.String("cryptopbkdfmemory"),
+				CryptoIntegrity:            c.String("crypto-integrity"),
+				CryptoHeaderPath:           c.String("crypto-header"),
+				CryptoHeaderInit:           c.Bool("crypto-header-init"),
 				FsType:                     c.String("fs"),
 				MountOptions:               c.StringSlice("mount"),
+				BtrfsSubvolume:             c.String("btrfs-subvolume"),
+				RootPermissions: volume.RootPermissions{
+					Mode:   rootMode,
+					UID:    c.Int("root-uid"),
+					GID:    c.Int("root-gid"),
+					Setgid: c.Bool("root-setgid"),
+					Policy: c.String("root-permissions"),
+				},
 			}
 
 			if c.Bool("encrypted") && len(vol.Passphrase) == 0 {
-				logrus.Fatalf("Error starting share-manager missing passphrase for encrypted volume %v", vol.Name)
+				return fmt.Errorf("missing passphrase for encrypted volume %v", vol.Name)
+			}
+
+			if vol.IsEncrypted() {
+				if err := validateCryptoOptions(vol); err != nil {
+					return errors.Wrapf(err, "invalid crypto options for encrypted volume %v", vol.Name)
+				}
+			}
+
+			auditLogger, err := audit.NewLogger(c.String("audit-log"))
+			if err != nil {
+				return errors.Wrap(err, "invalid audit log")
+			}
+
+			shutdownTracing, err := tracing.Setup(ctx, c.String("tracing-exporter"), attribute.String("longhorn.volume", vol.Name))
+			if err != nil {
+				return errors.Wrap(err, "invalid tracing options")
 			}
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+				defer cancel()
+				if err := shutdownTracing(ctx); err != nil {
+					logrus.WithError(err).Warn("Failed to flush trace spans")
+				}
+			}()
 
-			if err := start(vol); err != nil {
-				logrus.Fatalf("Error running start command: %v.", err)
+			serverOpts, err := grpcServerOptions(c, vol, auditLogger)
+			if err != nil {
+				return errors.Wrap(err, "invalid gRPC server options")
 			}
 
-			return nil
+			// errors are returned instead of exiting so the deferred trace flush still runs
+			return start(vol, auditLogger, serverOpts, tuningFromFlags(c), c.Duration("drain-timeout"))
 		},
 	}
 }
 
-func start(vol volume.Volume) error {
+func validateRootMode(mode string) error {
+	_, err := volume.ParseRootMode(mode)
+	return err
+}
+
+// validateCryptoOptions checks the crypto options before anything touches the device, so a typo
+// in the LUKS format options fails the start instead of the format of a new volume. Whether the
+// kernel supports the cipher is only checked when formatting.
+func validateCryptoOptions(vol volume.Volume) error {
+	if vol.CryptoHeaderPath != "" && !filepath.IsAbs(vol.CryptoHeaderPath) {
+		return fmt.Errorf("crypto header path %v is not absolute", vol.CryptoHeaderPath)
+	}
+	if _, err := crypto.ParseLuksFormatOptions(vol.LuksFormatOptions(), vol.CryptoIntegrity); err != nil {
+		return err
+	}
+	return nil
+}
+
+// grpcServerOptions returns the gRPC server options for tracing, transport security, auditing and authorization.
+// Without a certificate the server keeps serving in plain text.
+func grpcServerOptions(c *cli.Command, vol volume.Volume, auditLogger *audit.Logger) ([]grpc.ServerOption, error) {
+	certFile := c.String("tls-cert")
+	keyFile := c.String("tls-key")
+	clientCAFile := c.String("tls-client-ca")
+	policyFile := c.String("authz-policy")
+	tokenFile := c.String("authz-token-file")
+
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	}
+	// the audit interceptor goes first to also record calls denied by the authorizer
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		rpc.AuditUnaryServerInterceptor(auditLogger, vol.Name),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		rpc.AuditStreamServerInterceptor(auditLogger, vol.Name),
+	}
+
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return nil, fmt.Errorf("client CA %v requires a server certificate", clientCAFile)
+		}
+		if tokenFile != "" {
+			return nil, fmt.Errorf("authorization token file %v requires a server certificate, bearer tokens are only accepted over TLS", tokenFile)
+		}
+		logrus.Warn("No TLS certificate configured, serving share manager gRPC server without transport security")
+	} else {
+		reloader, err := util.NewCertificateReloader(certFile, keyFile, clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+	}
+
+	if policyFile == "" {
+		if tokenFile != "" {
+			return nil, fmt.Errorf("authorization token file %v requires an authorization policy", tokenFile)
+		}
+	} else {
+		authorizer, err := rpc.NewAuthorizer(util.NewLogger(), policyFile, tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		unaryInterceptors = append(unaryInterceptors, authorizer.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, authorizer.StreamServerInterceptor())
+	}
+
+	return append(opts,
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	), nil
+}
+
+func start(vol volume.Volume, auditLogger *audit.Logger, serverOpts []grpc.ServerOption, tuning nfs.Tuning, drainTimeout time.Duration) error {
 	logger := util.NewLogger()
 	if vol.DataEngine != types.DataEngineTypeV1 && vol.DataEngine != types.DataEngineTypeV2 {
 		logger.Errorf("Invalid data engine value: %s", vol.DataEngine)
 		return fmt.Errorf("invalid data engine value: %s", vol.DataEngine)
 	}
 
-	manager, err := server.NewShareManager(logger, vol)
+	manager, err := server.NewShareManager(logger, vol, auditLogger, tuning)
 	if err != nil {
 		return err
 	}
@@ -156,9 +395,10 @@ func start(vol volume.Volume) error {
 			return
 		}
 
-		s := grpc.NewServer()
+		s := grpc.NewServer(serverOpts...)
 		srv := rpc.NewShareManagerServer(manager)
 		smrpc.RegisterShareManagerServiceServer(s, srv)
+		smextrpc.RegisterShareManagerExtensionServiceServer(s, srv)
 		healthpb.RegisterHealthServer(s, rpc.NewShareManagerHealthCheckServer(srv))
 		reflection.Register(s)
 
@@ -173,7 +413,12 @@ func start(vol volume.Volume) error {
 	go func() {
 		sig := <-sigs
 		logger.Infof("share manager received signal %v to exit", sig)
-		manager.Shutdown()
+		if drainTimeout > 0 {
+			if err := manager.Drain(drainTimeout); err != nil {
+				logger.WithError(err).Warn("Failed to drain share manager, shutting down anyway")
+			}
+		}
+		manager.Shutdown(fmt.Sprintf("received signal %v", sig))
 	}()
 
 	return <-shutdownCh
@@ -45,6 +45,15 @@ func main() {
 		},
 		Commands: []*cli.Command{
 			cmd.ServerCmd(),
+			cmd.StatusCmd(),
+			cmd.HealthCmd(),
+			cmd.MountCmd(),
+			cmd.UnmountCmd(),
+			cmd.TrimCmd(),
+			cmd.ResizeCmd(),
+			cmd.ClientsCmd(),
+			cmd.RenderConfigCmd(),
+			cmd.ValidateConfigCmd(),
 		},
 	}
 	if err := a.Run(context.Background(), os.Args); err != nil {
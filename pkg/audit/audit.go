@@ -0,0 +1,104 @@
+// Package audit writes a record of every operation changing the share as JSON lines,
+// so post-incident reviews can tell who did what and when.
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DestinationStdout selects stdout as the destination of the audit log
+	DestinationStdout = "-"
+
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+
+	EventRPC              = "rpc"
+	EventLeaseTakeover    = "lease-takeover"
+	EventReadOnlyRecovery = "read-only-recovery"
+	EventShutdown         = "shutdown"
+	EventDrain            = "drain"
+)
+
+// Entry is a single line of the audit log.
+type Entry struct {
+	Event    string            `json:"event"`
+	Volume   string            `json:"volume"`
+	Method   string            `json:"method,omitempty"`
+	Peer     string            `json:"peer,omitempty"`
+	Identity string            `json:"identity,omitempty"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Outcome  string            `json:"outcome"`
+	Error    string            `json:"error,omitempty"`
+	Details  map[string]string `json:"details,omitempty"`
+}
+
+// Logger writes audit entries. A nil Logger discards all entries,
+// so callers don't need to check whether auditing is enabled.
+type Logger struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewLogger returns a logger writing to stdout for DestinationStdout or appending to the
+// file at destination otherwise. An empty destination disables the audit log.
+func NewLogger(destination string) (*Logger, error) {
+	switch destination {
+	case "":
+		return nil, nil
+	case DestinationStdout:
+		return &Logger{writer: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(destination, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open audit log %v", destination)
+	}
+	return &Logger{writer: file}, nil
+}
+
+// Log writes the entry as one JSON line, failures are only reported to the regular log
+// since an operation shouldn't fail because it couldn't be audited.
+func (l *Logger) Log(entry Entry) {
+	if l == nil {
+		return
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		logrus.WithError(err).Errorf("Failed to encode audit entry %+v", entry)
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if _, err := l.writer.Write(append(line, '\n')); err != nil {
+		logrus.WithError(err).Errorf("Failed to write audit entry %s", line)
+	}
+}
+
+// LogResult writes an entry for an operation that ran from start until now,
+// the outcome is derived from err.
+func (l *Logger) LogResult(entry Entry, start time.Time, err error) {
+	if l == nil {
+		return
+	}
+
+	entry.Start = start
+	entry.End = time.Now()
+	entry.Outcome = OutcomeSuccess
+	if err != nil {
+		entry.Outcome = OutcomeFailure
+		entry.Error = err.Error()
+	}
+	l.Log(entry)
+}
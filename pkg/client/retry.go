@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+// RetryPolicy controls how calls failing with Unavailable or DeadlineExceeded are retried.
+// Calls which are not safe to repeat are never retried.
+type RetryPolicy struct {
+	// MaxAttempts includes the first call, 1 disables retries
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// AttemptTimeout applies to each attempt if the context of the call has no deadline
+	AttemptTimeout time.Duration
+}
+
+// DefaultRetryPolicy covers a share manager pod restarting or briefly unreachable.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	AttemptTimeout: types.GRPCServiceTimeout,
+}
+
+// WithRetryPolicy replaces the DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retry = policy
+	}
+}
+
+// nextBackoff doubles the backoff up to the maximum
+func (p RetryPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	return min(2*backoff, p.MaxBackoff)
+}
+
+// retryMode tells which failures of a call are retried
+type retryMode int
+
+const (
+	// retryNever is for calls which are not safe to repeat
+	retryNever retryMode = iota
+	// retryUnavailable only retries calls which did not reach the server. A call which timed out
+	// may still be running on the server, so it is not repeated.
+	retryUnavailable
+	// retryTransient retries calls which did not reach the server or timed out
+	retryTransient
+)
+
+func isRetryable(err error, mode retryMode) bool {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return mode != retryNever
+	case codes.DeadlineExceeded:
+		return mode == retryTransient
+	}
+	return false
+}
+
+// sleep waits for the backoff with up to 20% jitter, so clients started together spread out
+func sleep(ctx context.Context, backoff time.Duration) error {
+	jitter := time.Duration(rand.Int64N(int64(backoff)/5 + 1))
+	timer := time.NewTimer(backoff + jitter)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (c *ShareManagerClient) call(ctx context.Context, mode retryMode, fn func(ctx context.Context) error) error {
+	_, err := callWithResult(c, ctx, mode, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// callWithResult runs fn and retries it with exponential backoff if it failed with an error
+// retryable in the mode. It gives up once ctx is done.
+func callWithResult[T any](c *ShareManagerClient, ctx context.Context, mode retryMode, fn func(ctx context.Context) (T, error)) (T, error) {
+	attempts := c.retry.MaxAttempts
+	if mode == retryNever || attempts < 1 {
+		attempts = 1
+	}
+
+	backoff := c.retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		result, err := callWithTimeout(ctx, c.retry.AttemptTimeout, fn)
+		if err == nil || attempt >= attempts || !isRetryable(err, mode) || ctx.Err() != nil {
+			return result, err
+		}
+
+		if sleep(ctx, backoff) != nil {
+			return result, err
+		}
+		backoff = c.retry.nextBackoff(backoff)
+	}
+}
+
+// callWithTimeout bounds a single attempt unless the caller already set a deadline
+func callWithTimeout[T any](ctx context.Context, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
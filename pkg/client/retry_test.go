@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	rpc "github.com/longhorn/types/pkg/generated/smrpc"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		mode retryMode
+		want bool
+	}{
+		{name: "unavailable", err: status.Error(codes.Unavailable, "connection refused"), mode: retryTransient, want: true},
+		{name: "deadline exceeded", err: status.Error(codes.DeadlineExceeded, "timeout"), mode: retryTransient, want: true},
+		{name: "internal", err: status.Error(codes.Internal, "failed"), mode: retryTransient},
+		{name: "not a status", err: errors.New("failed"), mode: retryTransient},
+		{name: "unavailable only", err: status.Error(codes.Unavailable, "connection refused"), mode: retryUnavailable, want: true},
+		{name: "deadline exceeded without retrying timeouts", err: status.Error(codes.DeadlineExceeded, "timeout"), mode: retryUnavailable},
+		{name: "unavailable never retried", err: status.Error(codes.Unavailable, "connection refused"), mode: retryNever},
+		{name: "deadline exceeded never retried", err: status.Error(codes.DeadlineExceeded, "timeout"), mode: retryNever},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err, tt.mode); got != tt.want {
+				t.Errorf("isRetryable(%v, %v) = %v, want %v", tt.err, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallWithResultAttempts(t *testing.T) {
+	c := &ShareManagerClient{retry: RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}}
+
+	tests := []struct {
+		name         string
+		mode         retryMode
+		err          error
+		wantAttempts int
+	}{
+		{name: "success", mode: retryTransient, wantAttempts: 1},
+		{name: "transient", mode: retryTransient, err: status.Error(codes.Unavailable, ""), wantAttempts: 3},
+		{name: "timeout", mode: retryTransient, err: status.Error(codes.DeadlineExceeded, ""), wantAttempts: 3},
+		{name: "timeout of unmount", mode: retryUnavailable, err: status.Error(codes.DeadlineExceeded, ""), wantAttempts: 1},
+		{name: "not retryable", mode: retryTransient, err: status.Error(codes.InvalidArgument, ""), wantAttempts: 1},
+		{name: "not safe to repeat", mode: retryNever, err: status.Error(codes.Unavailable, ""), wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := c.call(context.Background(), tt.mode, func(ctx context.Context) error {
+				attempts++
+				return tt.err
+			})
+			if err != tt.err {
+				t.Errorf("call() error = %v, want %v", err, tt.err)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("call() made %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+type fakeShareManagerServiceClient struct {
+	rpc.ShareManagerServiceClient
+	err      error
+	attempts int
+}
+
+func (f *fakeShareManagerServiceClient) FilesystemTrim(ctx context.Context, req *rpc.FilesystemTrimRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	f.attempts++
+	return nil, f.err
+}
+
+func (f *fakeShareManagerServiceClient) FilesystemResize(ctx context.Context, req *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	f.attempts++
+	return nil, f.err
+}
+
+func TestFilesystemCallAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         func(c *ShareManagerClient) error
+		err          error
+		wantAttempts int
+	}{
+		{
+			name:         "trim not reached",
+			call:         func(c *ShareManagerClient) error { return c.FilesystemTrimWithContext(context.Background(), false) },
+			err:          status.Error(codes.Unavailable, ""),
+			wantAttempts: 3,
+		},
+		{
+			name:         "trim timed out",
+			call:         func(c *ShareManagerClient) error { return c.FilesystemTrimWithContext(context.Background(), false) },
+			err:          status.Error(codes.DeadlineExceeded, ""),
+			wantAttempts: 1,
+		},
+		{
+			name:         "resize timed out",
+			call:         func(c *ShareManagerClient) error { return c.FilesystemResizeWithContext(context.Background()) },
+			err:          status.Error(codes.DeadlineExceeded, ""),
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeShareManagerServiceClient{err: tt.err}
+			c := &ShareManagerClient{
+				client: fake,
+				retry:  RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+			}
+			if err := tt.call(c); err != tt.err {
+				t.Errorf("call error = %v, want %v", err, tt.err)
+			}
+			if fake.attempts != tt.wantAttempts {
+				t.Errorf("made %d attempts, want %d", fake.attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
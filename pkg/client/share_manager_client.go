@@ -2,48 +2,167 @@ package client
 
 import (
 	"context"
+	"net"
+	"os"
+	"strings"
 
 	"github.com/cockroachdb/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	rpc "github.com/longhorn/types/pkg/generated/smrpc"
 
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
 	"github.com/longhorn/longhorn-share-manager/pkg/types"
+	"github.com/longhorn/longhorn-share-manager/pkg/util"
 )
 
 type ShareManagerClient struct {
 	address string
 	conn    *grpc.ClientConn
 	client  rpc.ShareManagerServiceClient
-	health  healthpb.HealthClient
+	// extClient serves the methods which are not part of the ShareManagerService yet
+	extClient smextrpc.ShareManagerExtensionServiceClient
+	health    healthpb.HealthClient
+	retry     RetryPolicy
 }
 
-func NewShareManagerClient(address string) (*ShareManagerClient, error) {
+type clientOptions struct {
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+	tokenFile  string
+	tls        bool
+	retry      RetryPolicy
+}
+
+// ClientOption configures the connection of a ShareManagerClient.
+type ClientOption func(*clientOptions)
+
+// WithTLS enables TLS and verifies the server certificate against the CA bundle,
+// or against the system roots if caFile is empty.
+func WithTLS(caFile string) ClientOption {
+	return func(o *clientOptions) {
+		o.tls = true
+		o.caFile = caFile
+	}
+}
+
+// WithClientCertificate presents the certificate to the server for mutual TLS,
+// the files are reloaded when they change. It implies WithTLS.
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(o *clientOptions) {
+		o.tls = true
+		o.certFile = certFile
+		o.keyFile = keyFile
+	}
+}
+
+// WithServerName overrides the name verified against the server certificate,
+// which defaults to the host of the address. It implies WithTLS.
+func WithServerName(serverName string) ClientOption {
+	return func(o *clientOptions) {
+		o.tls = true
+		o.serverName = serverName
+	}
+}
+
+// WithBearerToken authenticates every call with the token read from the file,
+// the file is read on each call so a rotated token is picked up. It implies WithTLS.
+func WithBearerToken(tokenFile string) ClientOption {
+	return func(o *clientOptions) {
+		o.tls = true
+		o.tokenFile = tokenFile
+	}
+}
+
+// tokenFileCredentials implements credentials.PerRPCCredentials
+type tokenFileCredentials struct {
+	tokenFile string
+}
+
+func (c tokenFileCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := os.ReadFile(c.tokenFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read bearer token %v", c.tokenFile)
+	}
+	return map[string]string{"authorization": "Bearer " + strings.TrimSpace(string(token))}, nil
+}
+
+func (c tokenFileCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+func NewShareManagerClient(address string, opts ...ClientOption) (*ShareManagerClient, error) {
+	options := &clientOptions{retry: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if options.tls {
+		reloader, err := util.NewCertificateReloader(options.certFile, options.keyFile, options.caFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load TLS files for share manager service %v", address)
+		}
+		serverName := options.serverName
+		if serverName == "" {
+			serverName = addressHost(address)
+		}
+		tlsConfig, err := reloader.ClientConfig(serverName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create TLS config for share manager service %v", address)
+		}
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	}
+
 	// Disable gRPC service config discovery to prevent DNS flooding in Kubernetes
-	conn, err := grpc.NewClient(
-		address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCredentials),
 		grpc.WithNoProxy(),
 		grpc.WithDisableServiceConfig(),
-	)
+		// spans are only exported if the caller set up a tracer provider
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	if options.tokenFile != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenFileCredentials{tokenFile: options.tokenFile}))
+	}
+
+	conn, err := grpc.NewClient(address, dialOpts...)
 
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to connect share manager service to %v", address)
 	}
 
 	return &ShareManagerClient{
-		address: address,
-		conn:    conn,
-		client:  rpc.NewShareManagerServiceClient(conn),
-		health:  healthpb.NewHealthClient(conn),
+		address:   address,
+		conn:      conn,
+		client:    rpc.NewShareManagerServiceClient(conn),
+		extClient: smextrpc.NewShareManagerExtensionServiceClient(conn),
+		health:    healthpb.NewHealthClient(conn),
+		retry:     options.retry,
 	}, nil
 }
 
+// addressHost returns the host of a target address such as "10.42.0.5:9600" or "dns:///name:9600"
+func addressHost(address string) string {
+	if _, target, ok := strings.Cut(address, ":///"); ok {
+		address = target
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
+}
+
 func (c *ShareManagerClient) Close() error {
 	if c.conn == nil {
 		return nil
@@ -53,45 +172,316 @@ func (c *ShareManagerClient) Close() error {
 }
 
 func (c *ShareManagerClient) FilesystemTrim(encryptedDevice bool) error {
-	ctx, cancel := context.WithTimeout(context.Background(), types.GRPCServiceTimeout)
-	defer cancel()
+	return c.FilesystemTrimWithContext(context.Background(), encryptedDevice)
+}
 
-	_, err := c.client.FilesystemTrim(ctx, &rpc.FilesystemTrimRequest{EncryptedDevice: encryptedDevice})
-	return err
+// FilesystemTrimWithContext runs the trim within the call, it is cancelled when ctx is done. It is
+// only retried when the server was not reached, a repeated call after a timeout would start the
+// trim over. Use StartFilesystemTrim for a trim which may outlast the call.
+func (c *ShareManagerClient) FilesystemTrimWithContext(ctx context.Context, encryptedDevice bool) error {
+	return c.call(ctx, retryUnavailable, func(ctx context.Context) error {
+		_, err := c.client.FilesystemTrim(ctx, &rpc.FilesystemTrimRequest{EncryptedDevice: encryptedDevice})
+		return err
+	})
 }
 
 func (c *ShareManagerClient) FilesystemResize() error {
-	ctx, cancel := context.WithTimeout(context.Background(), types.GRPCServiceTimeout)
-	defer cancel()
+	return c.FilesystemResizeWithContext(context.Background())
+}
 
-	_, err := c.client.FilesystemResize(ctx, &emptypb.Empty{})
-	return err
+// FilesystemResizeWithContext runs the resize within the call and is only retried when the server
+// was not reached. Use StartFilesystemResize for a resize which may outlast the call.
+func (c *ShareManagerClient) FilesystemResizeWithContext(ctx context.Context) error {
+	return c.call(ctx, retryUnavailable, func(ctx context.Context) error {
+		_, err := c.client.FilesystemResize(ctx, &emptypb.Empty{})
+		return err
+	})
 }
 
 func (c *ShareManagerClient) Unmount() error {
-	ctx, cancel := context.WithTimeout(context.Background(), types.GRPCServiceTimeout)
-	defer cancel()
+	return c.UnmountWithContext(context.Background())
+}
 
-	_, err := c.client.Unmount(ctx, &emptypb.Empty{})
-	return err
+// UnmountWithContext is not retried after a timeout, the unmount may still be running on the server
+// and a repeated call would race with it.
+func (c *ShareManagerClient) UnmountWithContext(ctx context.Context) error {
+	return c.call(ctx, retryUnavailable, func(ctx context.Context) error {
+		_, err := c.client.Unmount(ctx, &emptypb.Empty{})
+		return err
+	})
 }
 
 func (c *ShareManagerClient) Mount() error {
-	ctx, cancel := context.WithTimeout(context.Background(), types.GRPCServiceTimeout)
-	defer cancel()
+	return c.MountWithContext(context.Background())
+}
 
-	_, err := c.client.Mount(ctx, &emptypb.Empty{})
-	return err
+func (c *ShareManagerClient) MountWithContext(ctx context.Context) error {
+	return c.call(ctx, retryTransient, func(ctx context.Context) error {
+		_, err := c.client.Mount(ctx, &emptypb.Empty{})
+		return err
+	})
 }
 
 func (c *ShareManagerClient) IsServing() (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), types.GRPCServiceTimeout)
-	defer cancel()
+	return c.IsServingWithContext(context.Background())
+}
 
-	resp, err := c.health.Check(ctx, &healthpb.HealthCheckRequest{})
+func (c *ShareManagerClient) IsServingWithContext(ctx context.Context) (bool, error) {
+	resp, err := callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*healthpb.HealthCheckResponse, error) {
+		return c.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	})
 	if err != nil {
 		return false, err
 	}
 
 	return resp.GetStatus() == healthpb.HealthCheckResponse_SERVING, nil
 }
+
+// WaitForServing blocks until the share manager reports serving or ctx is done. The health
+// stream is reopened with backoff while the server is not reachable yet.
+func (c *ShareManagerClient) WaitForServing(ctx context.Context) error {
+	backoff := c.retry.InitialBackoff
+	for {
+		serving, err := c.watchUntilServing(ctx)
+		if serving {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err == nil {
+				err = ctxErr
+			}
+			return errors.Wrapf(err, "share manager service %v is not serving", c.address)
+		}
+		if !isRetryable(err, retryTransient) {
+			return err
+		}
+
+		if err := sleep(ctx, backoff); err != nil {
+			return errors.Wrapf(err, "share manager service %v is not serving", c.address)
+		}
+		backoff = c.retry.nextBackoff(backoff)
+	}
+}
+
+func (c *ShareManagerClient) watchUntilServing(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.health.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return false, err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return false, err
+		}
+		if resp.GetStatus() == healthpb.HealthCheckResponse_SERVING {
+			return true, nil
+		}
+	}
+}
+
+func (c *ShareManagerClient) GetEncryptionStatus() (*smextrpc.GetEncryptionStatusResponse, error) {
+	return c.GetEncryptionStatusWithContext(context.Background())
+}
+
+func (c *ShareManagerClient) GetEncryptionStatusWithContext(ctx context.Context) (*smextrpc.GetEncryptionStatusResponse, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.GetEncryptionStatusResponse, error) {
+		return c.extClient.GetEncryptionStatus(ctx, &smextrpc.GetEncryptionStatusRequest{})
+	})
+}
+
+// DestroyEncryption crypto-shreds the volume, volumeName has to match the volume of the share
+// manager. It is never retried, a repeated call after a lost response would fail since the LUKS
+// header is already gone.
+func (c *ShareManagerClient) DestroyEncryption(volumeName string) (*smextrpc.DestroyEncryptionResponse, error) {
+	return c.DestroyEncryptionWithContext(context.Background(), volumeName)
+}
+
+func (c *ShareManagerClient) DestroyEncryptionWithContext(ctx context.Context, volumeName string) (*smextrpc.DestroyEncryptionResponse, error) {
+	return callWithResult(c, ctx, retryNever, func(ctx context.Context) (*smextrpc.DestroyEncryptionResponse, error) {
+		return c.extClient.DestroyEncryption(ctx, &smextrpc.DestroyEncryptionRequest{VolumeName: volumeName})
+	})
+}
+
+// StartFilesystemTrim starts trimming the filesystem in the background and returns the operation.
+func (c *ShareManagerClient) StartFilesystemTrim(encryptedDevice bool) (*smextrpc.Operation, error) {
+	return c.StartFilesystemTrimWithContext(context.Background(), encryptedDevice)
+}
+
+func (c *ShareManagerClient) StartFilesystemTrimWithContext(ctx context.Context, encryptedDevice bool) (*smextrpc.Operation, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.Operation, error) {
+		return c.extClient.StartFilesystemTrim(ctx, &smextrpc.StartFilesystemTrimRequest{EncryptedDevice: encryptedDevice})
+	})
+}
+
+// StartFilesystemResize starts resizing the filesystem in the background and returns the operation.
+func (c *ShareManagerClient) StartFilesystemResize() (*smextrpc.Operation, error) {
+	return c.StartFilesystemResizeWithContext(context.Background())
+}
+
+func (c *ShareManagerClient) StartFilesystemResizeWithContext(ctx context.Context) (*smextrpc.Operation, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.Operation, error) {
+		return c.extClient.StartFilesystemResize(ctx, &smextrpc.StartFilesystemResizeRequest{})
+	})
+}
+
+func (c *ShareManagerClient) GetOperation(id string) (*smextrpc.Operation, error) {
+	return c.GetOperationWithContext(context.Background(), id)
+}
+
+func (c *ShareManagerClient) GetOperationWithContext(ctx context.Context, id string) (*smextrpc.Operation, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.Operation, error) {
+		return c.extClient.GetOperation(ctx, &smextrpc.GetOperationRequest{Id: id})
+	})
+}
+
+func (c *ShareManagerClient) ListOperations() ([]*smextrpc.Operation, error) {
+	return c.ListOperationsWithContext(context.Background())
+}
+
+func (c *ShareManagerClient) ListOperationsWithContext(ctx context.Context) ([]*smextrpc.Operation, error) {
+	resp, err := callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.ListOperationsResponse, error) {
+		return c.extClient.ListOperations(ctx, &smextrpc.ListOperationsRequest{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Operations, nil
+}
+
+func (c *ShareManagerClient) CancelOperation(id string) (*smextrpc.Operation, error) {
+	return c.CancelOperationWithContext(context.Background(), id)
+}
+
+func (c *ShareManagerClient) CancelOperationWithContext(ctx context.Context, id string) (*smextrpc.Operation, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.Operation, error) {
+		return c.extClient.CancelOperation(ctx, &smextrpc.CancelOperationRequest{Id: id})
+	})
+}
+
+// WatchOperation calls update with every change of the operation until it is done or ctx
+// is cancelled, and returns the final state.
+func (c *ShareManagerClient) WatchOperation(ctx context.Context, id string, update func(*smextrpc.Operation)) (*smextrpc.Operation, error) {
+	stream, err := c.extClient.WatchOperation(ctx, &smextrpc.WatchOperationRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if update != nil {
+			update(op)
+		}
+		if types.IsOperationDone(op.State) {
+			return op, nil
+		}
+	}
+}
+
+func (c *ShareManagerClient) GetStatus(ctx context.Context) (*smextrpc.GetStatusResponse, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.GetStatusResponse, error) {
+		return c.extClient.GetStatus(ctx, &smextrpc.GetStatusRequest{})
+	})
+}
+
+func (c *ShareManagerClient) ListClients(ctx context.Context) ([]*smextrpc.NFSClient, error) {
+	resp, err := callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.ListClientsResponse, error) {
+		return c.extClient.ListClients(ctx, &smextrpc.ListClientsRequest{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Clients, nil
+}
+
+// CreateSubExport exports a directory of the volume and returns the sub-export with its defaults
+// and export id. It is not retried, a repeated call fails with AlreadyExists.
+func (c *ShareManagerClient) CreateSubExport(ctx context.Context, subExport *smextrpc.SubExport) (*smextrpc.SubExport, error) {
+	return callWithResult(c, ctx, retryNever, func(ctx context.Context) (*smextrpc.SubExport, error) {
+		return c.extClient.CreateSubExport(ctx, &smextrpc.CreateSubExportRequest{SubExport: subExport})
+	})
+}
+
+func (c *ShareManagerClient) ListSubExports(ctx context.Context) ([]*smextrpc.SubExport, error) {
+	resp, err := callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.ListSubExportsResponse, error) {
+		return c.extClient.ListSubExports(ctx, &smextrpc.ListSubExportsRequest{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.SubExports, nil
+}
+
+// DeleteSubExport unexports the sub-export, the directory and its data are kept.
+// It is not retried, a repeated call fails with NotFound.
+func (c *ShareManagerClient) DeleteSubExport(ctx context.Context, name string) error {
+	return c.call(ctx, retryNever, func(ctx context.Context) error {
+		_, err := c.extClient.DeleteSubExport(ctx, &smextrpc.DeleteSubExportRequest{Name: name})
+		return err
+	})
+}
+
+// ExportSnapshot mounts the attached device of a snapshot read-only and exports it below the volume.
+// It is not retried, a repeated call fails with AlreadyExists.
+func (c *ShareManagerClient) ExportSnapshot(ctx context.Context, snapshot *smextrpc.SnapshotExport) (*smextrpc.SnapshotExport, error) {
+	return callWithResult(c, ctx, retryNever, func(ctx context.Context) (*smextrpc.SnapshotExport, error) {
+		return c.extClient.ExportSnapshot(ctx, &smextrpc.ExportSnapshotRequest{Snapshot: snapshot})
+	})
+}
+
+func (c *ShareManagerClient) ListSnapshotExports(ctx context.Context) ([]*smextrpc.SnapshotExport, error) {
+	resp, err := callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.ListSnapshotExportsResponse, error) {
+		return c.extClient.ListSnapshotExports(ctx, &smextrpc.ListSnapshotExportsRequest{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Snapshots, nil
+}
+
+// UnexportSnapshot removes the export of the snapshot and unmounts it, so its device can be detached.
+// It is not retried, a repeated call fails with NotFound.
+func (c *ShareManagerClient) UnexportSnapshot(ctx context.Context, name string) error {
+	return c.call(ctx, retryNever, func(ctx context.Context) error {
+		_, err := c.extClient.UnexportSnapshot(ctx, &smextrpc.UnexportSnapshotRequest{Name: name})
+		return err
+	})
+}
+
+// SetDirectoryProject starts assigning a directory of the volume and everything below it to the
+// project, the returned operation can be watched until it is done.
+func (c *ShareManagerClient) SetDirectoryProject(ctx context.Context, path string, projectID uint32) (*smextrpc.Operation, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.Operation, error) {
+		return c.extClient.SetDirectoryProject(ctx, &smextrpc.SetDirectoryProjectRequest{Path: path, ProjectId: projectID})
+	})
+}
+
+// SetProjectQuota sets the limits of the project, block limits are in bytes and 0 means unlimited.
+func (c *ShareManagerClient) SetProjectQuota(ctx context.Context, req *smextrpc.SetProjectQuotaRequest) (*smextrpc.ProjectQuota, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.ProjectQuota, error) {
+		return c.extClient.SetProjectQuota(ctx, req)
+	})
+}
+
+// GetProjectQuota returns the limits and usage of the project, or of the project of the directory if path is set.
+func (c *ShareManagerClient) GetProjectQuota(ctx context.Context, projectID uint32, path string) (*smextrpc.ProjectQuota, error) {
+	return callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*smextrpc.ProjectQuota, error) {
+		return c.extClient.GetProjectQuota(ctx, &smextrpc.GetProjectQuotaRequest{ProjectId: projectID, Path: path})
+	})
+}
+
+// CheckHealth returns the health of the service, an empty name refers to the share manager itself.
+func (c *ShareManagerClient) CheckHealth(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	resp, err := callWithResult(c, ctx, retryTransient, func(ctx context.Context) (*healthpb.HealthCheckResponse, error) {
+		return c.health.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	})
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, err
+	}
+	return resp.GetStatus(), nil
+}
@@ -0,0 +1,23 @@
+package client
+
+import "testing"
+
+func TestAddressHost(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{address: "10.42.0.5:9600", want: "10.42.0.5"},
+		{address: "[fd00::5]:9600", want: "fd00::5"},
+		{address: "dns:///share-manager-pvc-1234.longhorn-system:9600", want: "share-manager-pvc-1234.longhorn-system"},
+		{address: "share-manager", want: "share-manager"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if got := addressHost(tt.address); got != tt.want {
+				t.Errorf("addressHost(%q) = %q, want %q", tt.address, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,36 +1,87 @@
 package crypto
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sys/unix"
 
 	lhns "github.com/longhorn/go-common-libs/ns"
 	lhtypes "github.com/longhorn/go-common-libs/types"
 
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
 	"github.com/longhorn/longhorn-share-manager/pkg/types"
 )
 
+const (
+	binaryDmsetup = "dmsetup"
+	binaryShred   = "shred"
+	binaryTest    = "test"
+
+	// luksIntegrityFormatTimeout covers the initial wipe of the integrity tags,
+	// which has to write the whole device.
+	luksIntegrityFormatTimeout = 12 * time.Hour
+)
+
 // EncryptVolume encrypts provided device with LUKS.
-func EncryptVolume(devicePath, passphrase string, options *lhns.LuksFormatOptions) error {
+// A non empty integrity enables LUKS2 authenticated encryption backed by dm-integrity.
+// A non empty headerPath stores the LUKS header in that file on the host instead of on the device,
+// the header is labeled with the device name so it can be matched to the volume on open.
+// The options were validated on start already, the kernel support of the cipher is only checked
+// here, since it only matters for the format.
+func EncryptVolume(ctx context.Context, devicePath, passphrase string, options *lhns.LuksFormatOptions, integrity, headerPath string) (err error) {
+	_, span := tracing.Start(ctx, "crypto.EncryptVolume", attribute.String("devicePath", devicePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	params, err := ParseLuksFormatOptions(options, integrity)
+	if err != nil {
+		return errors.Wrap(err, "invalid crypto options")
+	}
+	if err := params.CheckKernelSupport(); err != nil {
+		return err
+	}
+
 	namespaces := []lhtypes.Namespace{lhtypes.NamespaceMnt, lhtypes.NamespaceIpc}
 	nsexec, err := lhns.NewNamespaceExecutor(lhtypes.ProcessNone, lhtypes.HostProcDirectory, namespaces)
 	if err != nil {
 		return err
 	}
 
-	logrus.WithFields(logrus.Fields{"device": devicePath, "options": options}).Debug("Encrypting device with LUKS")
-	if _, err := nsexec.LuksFormat(devicePath, passphrase, options, lhtypes.LuksTimeout); err != nil {
-		return errors.Wrapf(err, "failed to encrypt device %s with LUKS", devicePath)
+	logrus.WithFields(logrus.Fields{"device": devicePath, "options": options, "integrity": integrity, "header": headerPath}).Debug("Encrypting device with LUKS")
+	timeout := lhtypes.LuksTimeout
+	if integrity != "" {
+		// The integrity tags are initialized by cryptsetup wiping the device, otherwise
+		// every read of a not yet written sector would fail with an integrity error.
+		timeout = luksIntegrityFormatTimeout
+	}
+	args := append(params.FormatArgs(headerPath, filepath.Base(devicePath)), devicePath, "-d", "-")
+	if _, err := nsexec.CryptsetupWithPassphrase(passphrase, args, timeout); err != nil {
+		return errors.Wrapf(err, "failed to encrypt device %s with LUKS, integrity %q and header %q", devicePath, integrity, headerPath)
 	}
 	return nil
 }
 
 // OpenVolume opens volume so that it can be used by the client.
 // devicePath is the path of the volume on the host that will be opened for instance '/dev/longhorn/volume1'
-func OpenVolume(volume, dataEngine, devicePath, passphrase string) error {
+// headerPath is the optional detached LUKS header on the host, it is validated before use.
+func OpenVolume(ctx context.Context, volume, dataEngine, devicePath, passphrase, headerPath string) (err error) {
+	ctx, span := tracing.Start(ctx, "crypto.OpenVolume", attribute.String("devicePath", devicePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	devPath := types.GetVolumeDevicePath(volume, dataEngine, true)
 	if isOpen, _ := IsDeviceOpen(devPath); isOpen {
 		logrus.Debugf("Device %s is already opened at %s", devicePath, devPath)
@@ -44,16 +95,59 @@ func OpenVolume(volume, dataEngine, devicePath, passphrase string) error {
 	}
 
 	encryptedDevName := types.GetEncryptVolumeName(volume, dataEngine)
-	logrus.Debugf("Opening device %s with LUKS on %s", devicePath, encryptedDevName)
-	_, err = nsexec.LuksOpen(encryptedDevName, devicePath, passphrase, lhtypes.LuksTimeout)
+	if headerPath != "" {
+		if err := ValidateDetachedHeader(ctx, headerPath, filepath.Base(devicePath)); err != nil {
+			return err
+		}
+		logrus.Debugf("Opening device %s with LUKS header %s on %s", devicePath, headerPath, encryptedDevName)
+		args := []string{"luksOpen", "--header", headerPath, devicePath, encryptedDevName, "-d", "-"}
+		_, err = nsexec.CryptsetupWithPassphrase(passphrase, args, lhtypes.LuksTimeout)
+	} else {
+		logrus.Debugf("Opening device %s with LUKS on %s", devicePath, encryptedDevName)
+		_, err = nsexec.LuksOpen(encryptedDevName, devicePath, passphrase, lhtypes.LuksTimeout)
+	}
 	if err != nil {
 		logrus.WithError(err).Warnf("Failed to open LUKS device %s to %s", devicePath, encryptedDevName)
 	}
 	return err
 }
 
+// OpenVolumeReadOnly opens the volume like OpenVolume, but the mapping refuses writes, so
+// nothing written through it can reach the device, for instance the journal replay of a mount.
+func OpenVolumeReadOnly(ctx context.Context, volume, dataEngine, devicePath, passphrase string) (err error) {
+	_, span := tracing.Start(ctx, "crypto.OpenVolumeReadOnly", attribute.String("devicePath", devicePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	devPath := types.GetVolumeDevicePath(volume, dataEngine, true)
+	if isOpen, _ := IsDeviceOpen(devPath); isOpen {
+		logrus.Debugf("Device %s is already opened at %s", devicePath, devPath)
+		return nil
+	}
+
+	namespaces := []lhtypes.Namespace{lhtypes.NamespaceMnt, lhtypes.NamespaceIpc}
+	nsexec, err := lhns.NewNamespaceExecutor(lhtypes.ProcessNone, lhtypes.HostProcDirectory, namespaces)
+	if err != nil {
+		return err
+	}
+
+	encryptedDevName := types.GetEncryptVolumeName(volume, dataEngine)
+	logrus.Debugf("Opening device %s read-only with LUKS on %s", devicePath, encryptedDevName)
+	args := []string{"luksOpen", "--readonly", devicePath, encryptedDevName, "-d", "-"}
+	if _, err = nsexec.CryptsetupWithPassphrase(passphrase, args, lhtypes.LuksTimeout); err != nil {
+		logrus.WithError(err).Warnf("Failed to open LUKS device %s read-only to %s", devicePath, encryptedDevName)
+	}
+	return err
+}
+
 // CloseVolume closes encrypted volume so it can be detached.
-func CloseVolume(volume, dataEngine string) error {
+func CloseVolume(ctx context.Context, volume, dataEngine string) (err error) {
+	_, span := tracing.Start(ctx, "crypto.CloseVolume", attribute.String("volume", volume))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	namespaces := []lhtypes.Namespace{lhtypes.NamespaceMnt, lhtypes.NamespaceIpc}
 	nsexec, err := lhns.NewNamespaceExecutor(lhtypes.ProcessNone, lhtypes.HostProcDirectory, namespaces)
 	if err != nil {
@@ -62,11 +156,28 @@ func CloseVolume(volume, dataEngine string) error {
 
 	deviceName := types.GetEncryptVolumeName(volume, dataEngine)
 	logrus.Debugf("Closing LUKS device %s", deviceName)
-	_, err = nsexec.LuksClose(deviceName, lhtypes.LuksTimeout)
-	return err
+	if _, err = nsexec.LuksClose(deviceName, lhtypes.LuksTimeout); err != nil {
+		return err
+	}
+
+	// cryptsetup removes the integrity device together with the crypto device,
+	// but one left behind by an interrupted close would block the next open.
+	integrityDeviceName := types.GetIntegrityDeviceName(volume, dataEngine)
+	if _, err := os.Stat(filepath.Join(types.MapperDevPath, integrityDeviceName)); err == nil {
+		logrus.Infof("Removing leftover integrity device %s", integrityDeviceName)
+		if _, err := nsexec.Execute(nil, binaryDmsetup, []string{"remove", integrityDeviceName}, lhtypes.LuksTimeout); err != nil {
+			return errors.Wrapf(err, "failed to remove integrity device %s", integrityDeviceName)
+		}
+	}
+	return nil
 }
 
-func ResizeEncryptoDevice(volume, dataEngine, passphrase string) error {
+func ResizeEncryptoDevice(ctx context.Context, volume, dataEngine, passphrase, headerPath string) (err error) {
+	_, span := tracing.Start(ctx, "crypto.ResizeEncryptoDevice", attribute.String("volume", volume))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	// devPath is the full path of the encrypted device on the host that will be resized
 	devPath := types.GetVolumeDevicePath(volume, dataEngine, true)
 	if isOpen, err := IsDeviceOpen(devPath); err != nil {
@@ -81,8 +192,56 @@ func ResizeEncryptoDevice(volume, dataEngine, passphrase string) error {
 		return err
 	}
 
-	_, err = nsexec.LuksResize(types.GetEncryptVolumeName(volume, dataEngine), passphrase, lhtypes.LuksTimeout)
-	return err
+	// For authenticated encryption cryptsetup grows the dm-integrity device below the crypto device as well.
+	if headerPath != "" {
+		args := []string{"resize", "--header", headerPath, types.GetEncryptVolumeName(volume, dataEngine)}
+		_, err = nsexec.CryptsetupWithPassphrase(passphrase, args, lhtypes.LuksTimeout)
+	} else {
+		_, err = nsexec.LuksResize(types.GetEncryptVolumeName(volume, dataEngine), passphrase, lhtypes.LuksTimeout)
+	}
+	if err != nil {
+		if HasIntegrityDevice(volume, dataEngine) {
+			return errors.Wrapf(err, "failed to resize volume %v encrypto device %s with integrity protection, "+
+				"cryptsetup and kernel must support resizing dm-integrity devices", volume, devPath)
+		}
+		return err
+	}
+	return nil
+}
+
+// HasIntegrityDevice determines if the volume has an active dm-integrity device.
+func HasIntegrityDevice(volume, dataEngine string) bool {
+	_, err := os.Stat(filepath.Join(types.MapperDevPath, types.GetIntegrityDeviceName(volume, dataEngine)))
+	return err == nil
+}
+
+// GetIntegrityMismatches returns the number of integrity mismatches the kernel
+// detected on the dm-integrity device of the volume since it was opened.
+func GetIntegrityMismatches(volume, dataEngine string) (uint64, error) {
+	namespaces := []lhtypes.Namespace{lhtypes.NamespaceMnt, lhtypes.NamespaceIpc}
+	nsexec, err := lhns.NewNamespaceExecutor(lhtypes.ProcessNone, lhtypes.HostProcDirectory, namespaces)
+	if err != nil {
+		return 0, err
+	}
+
+	// Sample output of `dmsetup status <name>_dif`:
+	//   0 2064384 integrity 0 2064384 -
+	// the fields are start, length, target type, mismatches, provided data sectors and recalculate sector.
+	deviceName := types.GetIntegrityDeviceName(volume, dataEngine)
+	stdout, err := nsexec.Execute(nil, binaryDmsetup, []string{"status", deviceName}, lhtypes.LuksTimeout)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get status of integrity device %s", deviceName)
+	}
+
+	fields := strings.Fields(stdout)
+	if len(fields) < 4 || fields[2] != "integrity" {
+		return 0, fmt.Errorf("integrity device %s status is badly formatted: %s", deviceName, stdout)
+	}
+	mismatches, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse mismatches of integrity device %s", deviceName)
+	}
+	return mismatches, nil
 }
 
 // IsDeviceOpen determines if encrypted device is already open.
@@ -147,3 +306,281 @@ func DeviceEncryptionStatus(devicePath string) (mappedDevice, mapper string, err
 	// Identified as LUKS, but failed to identify a mapped device
 	return "", "", fmt.Errorf("mapped device not found in path %s", devicePath)
 }
+
+// EraseVolume makes the data on the device unrecoverable by erasing all LUKS keyslots
+// with `cryptsetup luksErase` and then overwriting the whole header area with zeros.
+// A detached header file is overwritten and removed instead.
+// The crypto device of the volume must be closed. It returns the erased header.
+func EraseVolume(ctx context.Context, devicePath, headerPath string) (header *LuksHeader, err error) {
+	ctx, span := tracing.Start(ctx, "crypto.EraseVolume", attribute.String("devicePath", devicePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	headerLocation := devicePath
+	if headerPath != "" {
+		headerLocation = headerPath
+	}
+
+	// read the header first, the data offset tells how much has to be wiped
+	header, err = GetLuksHeader(ctx, headerLocation)
+	if err != nil {
+		return nil, err
+	}
+	if header.Offset <= 0 && headerPath == "" {
+		return nil, fmt.Errorf("cannot determine LUKS header size of device %s", devicePath)
+	}
+
+	namespaces := []lhtypes.Namespace{lhtypes.NamespaceMnt, lhtypes.NamespaceIpc}
+	nsexec, err := lhns.NewNamespaceExecutor(lhtypes.ProcessNone, lhtypes.HostProcDirectory, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Erasing LUKS keyslots %v of %s", header.ActiveKeyslots, headerLocation)
+	if _, err := nsexec.Cryptsetup([]string{"-q", "luksErase", headerLocation}, lhtypes.LuksTimeout); err != nil {
+		return nil, errors.Wrapf(err, "failed to erase LUKS keyslots of %s", headerLocation)
+	}
+
+	if headerPath != "" {
+		// the header file lives on the host, so it is overwritten from the host namespace
+		logrus.Infof("Wiping and removing detached LUKS header %s", headerPath)
+		if _, err := nsexec.Execute(nil, binaryShred, []string{"--iterations=0", "--zero", "--remove", headerPath}, lhtypes.LuksTimeout); err != nil {
+			return nil, errors.Wrapf(err, "failed to wipe detached LUKS header %s", headerPath)
+		}
+		return header, nil
+	}
+
+	logrus.Infof("Wiping %d bytes of LUKS header of device %s", header.Offset, devicePath)
+	if err := wipeDevice(devicePath, header.Offset); err != nil {
+		return nil, errors.Wrapf(err, "failed to wipe LUKS header of device %s", devicePath)
+	}
+	return header, nil
+}
+
+func wipeDevice(devicePath string, length int64) (err error) {
+	device, err := os.OpenFile(devicePath, os.O_WRONLY|unix.O_EXCL, 0)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errClose := device.Close(); errClose != nil && err == nil {
+			err = errClose
+		}
+	}()
+
+	zeros := make([]byte, 1024*1024)
+	for written := int64(0); written < length; {
+		chunk := min(int64(len(zeros)), length-written)
+		n, err := device.Write(zeros[:chunk])
+		if err != nil {
+			return err
+		}
+		written += int64(n)
+	}
+	return device.Sync()
+}
+
+// DetachedHeaderExists determines if the detached LUKS header file exists on the host.
+func DetachedHeaderExists(headerPath string) (bool, error) {
+	namespaces := []lhtypes.Namespace{lhtypes.NamespaceMnt, lhtypes.NamespaceIpc}
+	nsexec, err := lhns.NewNamespaceExecutor(lhtypes.ProcessNone, lhtypes.HostProcDirectory, namespaces)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := nsexec.Execute(nil, binaryTest, []string{"-e", headerPath}, lhtypes.LuksTimeout); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to check detached LUKS header %s", headerPath)
+	}
+	return true, nil
+}
+
+// ValidateDetachedHeader verifies the file is a LUKS header and belongs to the device with the given label.
+func ValidateDetachedHeader(ctx context.Context, headerPath, label string) error {
+	header, err := GetLuksHeader(ctx, headerPath)
+	if err != nil {
+		return errors.Wrapf(err, "invalid detached LUKS header %s", headerPath)
+	}
+	if header.Label != label {
+		return fmt.Errorf("detached LUKS header %s belongs to %q instead of %q", headerPath, header.Label, label)
+	}
+	if len(header.ActiveKeyslots) == 0 {
+		return fmt.Errorf("detached LUKS header %s has no active keyslots", headerPath)
+	}
+	return nil
+}
+
+// LuksHeader contains the parameters read from the LUKS header of a device.
+type LuksHeader struct {
+	Version        string
+	Label          string
+	Cipher         string
+	KeySize        int // in bits
+	PBKDF          string
+	Hash           string
+	Integrity      string
+	ActiveKeyslots []int
+	SectorSize     int   // in bytes
+	Offset         int64 // in bytes
+}
+
+var (
+	luks2KeyslotRegex = regexp.MustCompile(`^\s+(\d+): luks2`)
+	luks1KeyslotRegex = regexp.MustCompile(`^Key Slot (\d+): ENABLED`)
+	leadingIntRegex   = regexp.MustCompile(`^(\d+)`)
+)
+
+// GetLuksHeader reads the LUKS header from the device or detached header file with `cryptsetup luksDump`,
+// so the result reflects what the volume actually uses rather than the requested options.
+func GetLuksHeader(ctx context.Context, path string) (header *LuksHeader, err error) {
+	_, span := tracing.Start(ctx, "crypto.GetLuksHeader", attribute.String("path", path))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	namespaces := []lhtypes.Namespace{lhtypes.NamespaceMnt, lhtypes.NamespaceIpc}
+	nsexec, err := lhns.NewNamespaceExecutor(lhtypes.ProcessNone, lhtypes.HostProcDirectory, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := nsexec.Cryptsetup([]string{"luksDump", path}, lhtypes.LuksTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dump LUKS header of %s", path)
+	}
+	return parseLuksDump(stdout)
+}
+
+// parseLuksDump parses the output of `cryptsetup luksDump`. Sample LUKS2 output:
+//
+//	Version:        2
+//	...
+//	Data segments:
+//	  0: crypt
+//	        offset: 16777216 [bytes]
+//	        length: (whole device)
+//	        cipher: aes-xts-plain64
+//	        sector: 512 [bytes]
+//	Keyslots:
+//	  0: luks2
+//	        Key:        512 bits
+//	        PBKDF:      argon2id
+//	        ...
+//	Digests:
+//	  0: pbkdf2
+//	        Hash:       sha256
+func parseLuksDump(dump string) (*LuksHeader, error) {
+	header := &LuksHeader{ActiveKeyslots: []int{}}
+	section := ""
+	luks1CipherName, luks1CipherMode := "", ""
+
+	for _, line := range strings.Split(dump, "\n") {
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && strings.HasSuffix(line, ":") {
+			section = strings.TrimSuffix(line, ":")
+			continue
+		}
+		if match := luks2KeyslotRegex.FindStringSubmatch(line); match != nil && section == "Keyslots" {
+			id, _ := strconv.Atoi(match[1])
+			header.ActiveKeyslots = append(header.ActiveKeyslots, id)
+			continue
+		}
+		if match := luks1KeyslotRegex.FindStringSubmatch(line); match != nil {
+			id, _ := strconv.Atoi(match[1])
+			header.ActiveKeyslots = append(header.ActiveKeyslots, id)
+			continue
+		}
+
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch {
+		case key == "Version":
+			header.Version = value
+		case key == "Label" && value != "(no label)":
+			header.Label = value
+		case key == "Cipher name":
+			luks1CipherName = value
+		case key == "Cipher mode":
+			luks1CipherMode = value
+		case key == "Hash spec":
+			header.Hash = value
+		case key == "MK bits":
+			header.KeySize = leadingInt(value)
+		case key == "Payload offset":
+			// LUKS1 reports the offset in 512 byte sectors
+			header.Offset = int64(leadingInt(value)) * 512
+		case section == "Data segments" && key == "offset" && header.Offset == 0:
+			header.Offset = int64(leadingInt(value))
+		case section == "Data segments" && key == "cipher" && header.Cipher == "":
+			header.Cipher = value
+		case section == "Data segments" && key == "sector" && header.SectorSize == 0:
+			header.SectorSize = leadingInt(value)
+		case section == "Data segments" && key == "integrity" && header.Integrity == "":
+			header.Integrity = value
+		case section == "Keyslots" && (key == "Key" || key == "Cipher key") && header.KeySize == 0:
+			header.KeySize = leadingInt(value)
+		case section == "Keyslots" && key == "PBKDF" && header.PBKDF == "":
+			header.PBKDF = value
+		case section == "Digests" && key == "Hash" && header.Hash == "":
+			header.Hash = value
+		}
+	}
+
+	if header.Version == "" {
+		return nil, fmt.Errorf("LUKS header dump is badly formatted, version not found")
+	}
+	if header.Version == "1" {
+		header.Cipher = luks1CipherName + "-" + luks1CipherMode
+		header.PBKDF = "pbkdf2"
+		header.SectorSize = 512
+	}
+	return header, nil
+}
+
+func leadingInt(value string) int {
+	match := leadingIntRegex.FindString(value)
+	v, _ := strconv.Atoi(match)
+	return v
+}
+
+// GetDeviceMode returns the access mode ro or rw of the open crypto device of the volume,
+// or an empty string if the device is not open.
+func GetDeviceMode(volume, dataEngine string) (string, error) {
+	devPath := types.GetVolumeDevicePath(volume, dataEngine, true)
+	if isOpen, err := IsDeviceOpen(devPath); err != nil || !isOpen {
+		return "", err
+	}
+
+	namespaces := []lhtypes.Namespace{lhtypes.NamespaceMnt, lhtypes.NamespaceIpc}
+	nsexec, err := lhns.NewNamespaceExecutor(lhtypes.ProcessNone, lhtypes.HostProcDirectory, namespaces)
+	if err != nil {
+		return "", err
+	}
+
+	stdout, err := nsexec.LuksStatus(types.GetEncryptVolumeName(volume, dataEngine), lhtypes.LuksTimeout)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(stdout, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found || strings.TrimSpace(key) != "mode" {
+			continue
+		}
+		if strings.Contains(value, "readonly") {
+			return "ro", nil
+		}
+		return "rw", nil
+	}
+	return "", fmt.Errorf("mode not found in status of crypto device %s", devPath)
+}
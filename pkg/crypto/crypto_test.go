@@ -0,0 +1,121 @@
+package crypto
+
+import (
+	"reflect"
+	"testing"
+)
+
+const luks2Dump = `LUKS header information
+Version:       	2
+Epoch:         	3
+Metadata area: 	16384 [bytes]
+Keyslots area: 	16744448 [bytes]
+UUID:          	0c1b3f4e-6a1f-4b34-9d5e-2b8b0e1f7a21
+Label:         	pvc-1234
+Subsystem:     	(no subsystem)
+Flags:       	(no flags)
+
+Data segments:
+  0: crypt
+	offset: 16777216 [bytes]
+	length: (whole device)
+	cipher: aes-xts-plain64
+	sector: 4096 [bytes]
+	integrity: hmac(sha256)
+
+Keyslots:
+  0: luks2
+	Key:        768 bits
+	Priority:   normal
+	Cipher:     aes-xts-plain64
+	Cipher key: 512 bits
+	PBKDF:      argon2id
+	Time cost:  4
+	Memory:     1048576
+	Threads:    4
+  2: luks2
+	Key:        768 bits
+	Priority:   normal
+	Cipher:     aes-xts-plain64
+	Cipher key: 512 bits
+	PBKDF:      argon2id
+Tokens:
+Digests:
+  0: pbkdf2
+	Hash:       sha256
+	Iterations: 129262
+`
+
+const luks1Dump = `LUKS header information for /dev/longhorn/vol
+
+Version:       	1
+Cipher name:   	aes
+Cipher mode:   	xts-plain64
+Hash spec:     	sha256
+Payload offset:	4096
+MK bits:       	512
+UUID:          	5f2b8f0e-9a8e-4e0b-8e6f-0d2f1c3b4a59
+
+Key Slot 0: ENABLED
+	Iterations:         	1000
+Key Slot 1: DISABLED
+Key Slot 2: ENABLED
+	Iterations:         	1000
+`
+
+func TestParseLuksDump(t *testing.T) {
+	tests := []struct {
+		name    string
+		dump    string
+		want    *LuksHeader
+		wantErr bool
+	}{
+		{
+			name: "luks2 with integrity",
+			dump: luks2Dump,
+			want: &LuksHeader{
+				Version:        "2",
+				Label:          "pvc-1234",
+				Cipher:         "aes-xts-plain64",
+				KeySize:        768,
+				PBKDF:          "argon2id",
+				Hash:           "sha256",
+				Integrity:      "hmac(sha256)",
+				ActiveKeyslots: []int{0, 2},
+				SectorSize:     4096,
+				Offset:         16777216,
+			},
+		},
+		{
+			name: "luks1",
+			dump: luks1Dump,
+			want: &LuksHeader{
+				Version:        "1",
+				Cipher:         "aes-xts-plain64",
+				KeySize:        512,
+				PBKDF:          "pbkdf2",
+				Hash:           "sha256",
+				ActiveKeyslots: []int{0, 2},
+				SectorSize:     512,
+				Offset:         4096 * 512,
+			},
+		},
+		{
+			name:    "not a luks dump",
+			dump:    "Device /dev/longhorn/vol is not a valid LUKS device.\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLuksDump(tt.dump)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLuksDump() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLuksDump() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
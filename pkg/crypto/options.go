@@ -0,0 +1,376 @@
+package crypto
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	lhns "github.com/longhorn/go-common-libs/ns"
+)
+
+const (
+	PBKDFArgon2i  = "argon2i"
+	PBKDFArgon2id = "argon2id"
+	PBKDFPbkdf2   = "pbkdf2"
+
+	// cryptsetup refuses argon2 iteration counts below 4 and pbkdf2 counts below 1000
+	minArgon2Iterations = 4
+	minPbkdf2Iterations = 1000
+
+	// cryptsetup limits the argon2 memory cost to the range [32 KiB, 4 GiB]
+	minPBKDFMemoryKiB = 32
+	maxPBKDFMemoryKiB = 4 * 1024 * 1024
+
+	maxKeySizeBits = 1024
+
+	IntegrityHMACSHA256 = "hmac-sha256"
+	IntegrityHMACSHA512 = "hmac-sha512"
+	IntegrityPoly1305   = "poly1305"
+	IntegrityAEAD       = "aead"
+)
+
+var xtsKeySizes = []int{256, 384, 512}
+
+var knownIntegrities = []string{
+	IntegrityHMACSHA256, IntegrityHMACSHA512, IntegrityPoly1305, IntegrityAEAD,
+}
+
+var knownBlockCiphers = []string{
+	"aes", "serpent", "twofish", "camellia", "cast5", "cast6", "sm4",
+	"xchacha12,aes", "xchacha20,aes", "chacha20",
+}
+
+var knownChainModes = []string{
+	"xts", "cbc", "ecb", "ctr", "lrw", "adiantum", "gcm",
+}
+
+var knownIVModes = []string{
+	"plain", "plain64", "plain64be", "benbi", "null", "lmk", "tcw", "eboiv", "elephant", "essiv", "random",
+}
+
+var knownHashes = []string{
+	"sha1", "sha224", "sha256", "sha384", "sha512",
+	"sha3-256", "sha3-384", "sha3-512",
+	"blake2b-256", "blake2b-512", "blake2s-256",
+	"ripemd160", "whirlpool", "stribog256", "stribog512", "sm3",
+}
+
+// LuksParameters is the typed form of the LUKS format options.
+// Zero values mean the cryptsetup default is used.
+type LuksParameters struct {
+	// Cipher is the full dm-crypt specification, which is split into the next three fields
+	Cipher               string
+	BlockCipher          string
+	ChainMode            string
+	IVMode               string
+	Hash                 string
+	KeySize              int
+	PBKDF                string
+	PBKDFForceIterations int
+	PBKDFMemory          int
+	Integrity            string
+}
+
+// ParseLuksFormatOptions parses and validates the free-form LUKS format options,
+// so a typo is reported before the device is touched instead of by cryptsetup.
+// The integrity mode is optional and enables LUKS2 authenticated encryption.
+func ParseLuksFormatOptions(options *lhns.LuksFormatOptions, integrity string) (*LuksParameters, error) {
+	params := &LuksParameters{}
+	if integrity != "" {
+		if !slices.Contains(knownIntegrities, integrity) {
+			return nil, fmt.Errorf("unknown crypto integrity %q, expected one of %v", integrity, knownIntegrities)
+		}
+		params.Integrity = integrity
+	}
+	if options == nil {
+		return params, params.Validate()
+	}
+
+	if options.KeyCipher != "" {
+		blockCipher, chainMode, ivMode, err := parseCipher(options.KeyCipher)
+		if err != nil {
+			return nil, err
+		}
+		params.Cipher = options.KeyCipher
+		params.BlockCipher = blockCipher
+		params.ChainMode = chainMode
+		params.IVMode = ivMode
+	}
+
+	if options.KeyHash != "" {
+		if !slices.Contains(knownHashes, options.KeyHash) {
+			return nil, fmt.Errorf("unknown crypto key hash %q, expected one of %v", options.KeyHash, knownHashes)
+		}
+		params.Hash = options.KeyHash
+	}
+
+	if options.KeySize != "" {
+		keySize, err := parsePositiveInt("crypto key size", options.KeySize)
+		if err != nil {
+			return nil, err
+		}
+		params.KeySize = keySize
+	}
+
+	if options.PBKDF != "" {
+		if !slices.Contains([]string{PBKDFArgon2i, PBKDFArgon2id, PBKDFPbkdf2}, options.PBKDF) {
+			return nil, fmt.Errorf("unknown crypto PBKDF %q, expected one of %v", options.PBKDF, []string{PBKDFArgon2i, PBKDFArgon2id, PBKDFPbkdf2})
+		}
+		params.PBKDF = options.PBKDF
+	}
+
+	if options.PBKDFForceIterations != "" {
+		iterations, err := parsePositiveInt("crypto PBKDF iterations", options.PBKDFForceIterations)
+		if err != nil {
+			return nil, err
+		}
+		params.PBKDFForceIterations = iterations
+	}
+
+	if options.PBKDFMemory != "" {
+		memory, err := parsePositiveInt("crypto PBKDF memory", options.PBKDFMemory)
+		if err != nil {
+			return nil, err
+		}
+		params.PBKDFMemory = memory
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// Validate checks the parameters for consistency with each other.
+func (p *LuksParameters) Validate() error {
+	if p.KeySize != 0 {
+		if p.KeySize%8 != 0 || p.KeySize > maxKeySizeBits {
+			return fmt.Errorf("invalid crypto key size %d, must be a multiple of 8 and at most %d bits", p.KeySize, maxKeySizeBits)
+		}
+		// with an hmac integrity mode cryptsetup derives the hmac key from the same volume key,
+		// so the key size covers both, for instance 768 bits for aes-256-xts with hmac-sha256
+		integrityKeySize := IntegrityKeySize(p.Integrity)
+		cipherKeySize := p.KeySize - integrityKeySize
+		if cipherKeySize <= 0 {
+			return fmt.Errorf("invalid crypto key size %d, must include the %d bits of the crypto integrity %v key and the cipher key",
+				p.KeySize, integrityKeySize, p.Integrity)
+		}
+		// xts splits the key in two halves, one for the data and one for the tweak,
+		// so each AES variant doubles: aes-128-xts, aes-192-xts and aes-256-xts
+		if p.ChainMode == "xts" && !slices.Contains(xtsKeySizes, cipherKeySize) {
+			return fmt.Errorf("invalid crypto key size %d for xts mode, must be one of %v bits plus %d bits for the crypto integrity",
+				p.KeySize, xtsKeySizes, integrityKeySize)
+		}
+		if p.BlockCipher == "aes" && p.ChainMode != "" && p.ChainMode != "xts" &&
+			cipherKeySize != 128 && cipherKeySize != 192 && cipherKeySize != 256 {
+			return fmt.Errorf("invalid crypto key size %d for aes, must be 128, 192 or 256 bits plus %d bits for the crypto integrity",
+				p.KeySize, integrityKeySize)
+		}
+	}
+
+	switch p.Integrity {
+	case IntegrityAEAD:
+		if p.ChainMode != "gcm" || p.IVMode != "random" {
+			return fmt.Errorf("crypto integrity %v requires an aead cipher such as aes-gcm-random", p.Integrity)
+		}
+	case IntegrityPoly1305:
+		if p.BlockCipher != "chacha20" || p.IVMode != "random" {
+			return fmt.Errorf("crypto integrity %v requires the chacha20-random cipher", p.Integrity)
+		}
+	case "":
+		if p.IVMode == "random" {
+			return fmt.Errorf("crypto key cipher with random IV mode requires a crypto integrity mode")
+		}
+	}
+
+	isArgon2 := p.PBKDF == "" || p.PBKDF == PBKDFArgon2i || p.PBKDF == PBKDFArgon2id
+	if p.PBKDFMemory != 0 {
+		if !isArgon2 {
+			return fmt.Errorf("crypto PBKDF memory cost is only supported by argon2, not %v", p.PBKDF)
+		}
+		if p.PBKDFMemory < minPBKDFMemoryKiB || p.PBKDFMemory > maxPBKDFMemoryKiB {
+			return fmt.Errorf("invalid crypto PBKDF memory %d KiB, must be between %d and %d", p.PBKDFMemory, minPBKDFMemoryKiB, maxPBKDFMemoryKiB)
+		}
+	}
+
+	if p.PBKDFForceIterations != 0 {
+		if isArgon2 && p.PBKDFForceIterations < minArgon2Iterations {
+			return fmt.Errorf("invalid crypto PBKDF iterations %d, argon2 requires at least %d", p.PBKDFForceIterations, minArgon2Iterations)
+		}
+		if !isArgon2 && p.PBKDFForceIterations < minPbkdf2Iterations {
+			return fmt.Errorf("invalid crypto PBKDF iterations %d, pbkdf2 requires at least %d", p.PBKDFForceIterations, minPbkdf2Iterations)
+		}
+	}
+
+	return nil
+}
+
+// IntegrityKeySize returns the bits of the volume key used by the integrity mode, only the hmac
+// modes have a key of their own, aead and poly1305 authenticate with the cipher key.
+func IntegrityKeySize(integrity string) int {
+	switch integrity {
+	case IntegrityHMACSHA256:
+		return 256
+	case IntegrityHMACSHA512:
+		return 512
+	}
+	return 0
+}
+
+// FormatArgs returns the arguments of `cryptsetup luksFormat` for the parameters, without the
+// device. A non empty headerPath detaches the header into that file, labeled with label.
+// lhns.LuksFormat has no integrity or header settings, so every format is built here.
+func (p *LuksParameters) FormatArgs(headerPath, label string) []string {
+	args := []string{"-q", "luksFormat", "--type", "luks2"}
+	if p.Cipher != "" {
+		args = append(args, "--cipher", p.Cipher)
+	}
+	if p.Hash != "" {
+		args = append(args, "--hash", p.Hash)
+	}
+	if p.KeySize != 0 {
+		args = append(args, "--key-size", strconv.Itoa(p.KeySize))
+	}
+	if p.PBKDF != "" {
+		args = append(args, "--pbkdf", p.PBKDF)
+	}
+	if p.PBKDFForceIterations != 0 {
+		args = append(args, "--pbkdf-force-iterations", strconv.Itoa(p.PBKDFForceIterations))
+	}
+	if p.PBKDFMemory != 0 {
+		args = append(args, "--pbkdf-memory", strconv.Itoa(p.PBKDFMemory))
+	}
+	if p.Integrity != "" {
+		args = append(args, "--integrity", p.Integrity)
+	}
+	if headerPath != "" {
+		args = append(args, "--header", headerPath, "--label", label)
+	}
+	return args
+}
+
+// KernelCipherName returns the kernel crypto API name of the cipher, for instance xts(aes) for aes-xts-plain64.
+func (p *LuksParameters) KernelCipherName() string {
+	if p.BlockCipher == "" {
+		return ""
+	}
+	if p.Integrity == IntegrityPoly1305 {
+		return "rfc7539(chacha20,poly1305)"
+	}
+	if p.ChainMode == "" {
+		return p.BlockCipher
+	}
+	return p.ChainMode + "(" + p.BlockCipher + ")"
+}
+
+// kernelHashNames maps the cryptsetup names of hashes to the kernel crypto API names, where they differ
+var kernelHashNames = map[string]string{
+	"ripemd160":  "rmd160",
+	"whirlpool":  "wp512",
+	"stribog256": "streebog256",
+	"stribog512": "streebog512",
+}
+
+// kernelHashName returns the kernel crypto API name of a hash, for instance rmd160 for ripemd160.
+func kernelHashName(hash string) string {
+	if name, ok := kernelHashNames[hash]; ok {
+		return name
+	}
+	return hash
+}
+
+// CheckKernelSupport verifies that the kernel crypto API can instantiate the cipher and hash.
+// Binding an AF_ALG socket makes the kernel load the required modules, so this is more
+// reliable than looking at /proc/crypto.
+func (p *LuksParameters) CheckKernelSupport() error {
+	cipherType := "skcipher"
+	if p.Integrity == IntegrityAEAD || p.Integrity == IntegrityPoly1305 {
+		cipherType = "aead"
+	}
+	if name := p.KernelCipherName(); name != "" {
+		if err := checkKernelAlgorithm(cipherType, name); err != nil {
+			return errors.Wrapf(err, "crypto key cipher %v is not available", name)
+		}
+	}
+	if p.Hash != "" {
+		if err := checkKernelAlgorithm("hash", kernelHashName(p.Hash)); err != nil {
+			return errors.Wrapf(err, "crypto key hash %v is not available", p.Hash)
+		}
+	}
+	if hash, ok := strings.CutPrefix(p.Integrity, "hmac-"); ok {
+		if err := checkKernelAlgorithm("hash", "hmac("+kernelHashName(hash)+")"); err != nil {
+			return errors.Wrapf(err, "crypto integrity %v is not available", p.Integrity)
+		}
+	}
+	return nil
+}
+
+func checkKernelAlgorithm(algType, name string) error {
+	fd, err := unix.Socket(unix.AF_ALG, unix.SOCK_SEQPACKET, 0)
+	if err != nil {
+		// Without AF_ALG we cannot probe, leave the final verdict to cryptsetup
+		logrus.WithError(err).Warnf("Cannot probe kernel crypto API for %v %v", algType, name)
+		return nil
+	}
+	defer func() {
+		if errClose := unix.Close(fd); errClose != nil {
+			logrus.WithError(errClose).Warn("Failed to close kernel crypto API socket")
+		}
+	}()
+
+	return unix.Bind(fd, &unix.SockaddrALG{Type: algType, Name: name})
+}
+
+// parseCipher splits a dm-crypt cipher specification cipher-chainmode-ivmode into its parts.
+func parseCipher(cipher string) (blockCipher, chainMode, ivMode string, err error) {
+	// chacha20 is a stream cipher and has no chain mode
+	if cipher == "chacha20-random" {
+		return "chacha20", "", "random", nil
+	}
+
+	parts := strings.SplitN(cipher, "-", 3)
+	blockCipher = parts[0]
+	if !slices.Contains(knownBlockCiphers, blockCipher) {
+		return "", "", "", fmt.Errorf("unknown crypto key cipher %q, block cipher %q is not one of %v", cipher, blockCipher, knownBlockCiphers)
+	}
+	if len(parts) < 2 {
+		return blockCipher, "", "", nil
+	}
+
+	chainMode = parts[1]
+	if !slices.Contains(knownChainModes, chainMode) {
+		return "", "", "", fmt.Errorf("unknown crypto key cipher %q, chain mode %q is not one of %v", cipher, chainMode, knownChainModes)
+	}
+	if len(parts) < 3 {
+		if chainMode != "ecb" {
+			return "", "", "", fmt.Errorf("invalid crypto key cipher %q, missing IV mode", cipher)
+		}
+		return blockCipher, chainMode, "", nil
+	}
+
+	ivMode = parts[2]
+	ivName, ivHash, hasIVHash := strings.Cut(ivMode, ":")
+	if !slices.Contains(knownIVModes, ivName) {
+		return "", "", "", fmt.Errorf("unknown crypto key cipher %q, IV mode %q is not one of %v", cipher, ivName, knownIVModes)
+	}
+	if ivName == "essiv" && (!hasIVHash || !slices.Contains(knownHashes, ivHash)) {
+		return "", "", "", fmt.Errorf("invalid crypto key cipher %q, essiv requires a known hash such as essiv:sha256", cipher)
+	}
+	return blockCipher, chainMode, ivMode, nil
+}
+
+func parsePositiveInt(name, value string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v %q, must be an integer", name, value)
+	}
+	if v <= 0 {
+		return 0, fmt.Errorf("invalid %v %d, must be positive", name, v)
+	}
+	return v, nil
+}
@@ -0,0 +1,160 @@
+package crypto
+
+import (
+	"slices"
+	"testing"
+
+	lhns "github.com/longhorn/go-common-libs/ns"
+)
+
+func TestParseCipher(t *testing.T) {
+	tests := []struct {
+		cipher      string
+		blockCipher string
+		chainMode   string
+		ivMode      string
+		wantErr     bool
+	}{
+		{cipher: "aes-xts-plain64", blockCipher: "aes", chainMode: "xts", ivMode: "plain64"},
+		{cipher: "aes-cbc-essiv:sha256", blockCipher: "aes", chainMode: "cbc", ivMode: "essiv:sha256"},
+		{cipher: "aes-gcm-random", blockCipher: "aes", chainMode: "gcm", ivMode: "random"},
+		{cipher: "chacha20-random", blockCipher: "chacha20", ivMode: "random"},
+		{cipher: "xchacha12,aes-adiantum-plain64", blockCipher: "xchacha12,aes", chainMode: "adiantum", ivMode: "plain64"},
+		{cipher: "aes-ecb", blockCipher: "aes", chainMode: "ecb"},
+		{cipher: "aes", blockCipher: "aes"},
+		{cipher: "des-cbc-plain", wantErr: true},
+		{cipher: "aes-foo-plain64", wantErr: true},
+		{cipher: "aes-xts", wantErr: true},
+		{cipher: "aes-xts-plain65", wantErr: true},
+		{cipher: "aes-cbc-essiv", wantErr: true},
+		{cipher: "aes-cbc-essiv:md5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cipher, func(t *testing.T) {
+			blockCipher, chainMode, ivMode, err := parseCipher(tt.cipher)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCipher(%q) error = %v, wantErr %v", tt.cipher, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if blockCipher != tt.blockCipher || chainMode != tt.chainMode || ivMode != tt.ivMode {
+				t.Errorf("parseCipher(%q) = %q, %q, %q, want %q, %q, %q", tt.cipher,
+					blockCipher, chainMode, ivMode, tt.blockCipher, tt.chainMode, tt.ivMode)
+			}
+		})
+	}
+}
+
+func TestParseLuksFormatOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   *lhns.LuksFormatOptions
+		integrity string
+		wantErr   bool
+	}{
+		{name: "no options"},
+		{name: "aes-128-xts", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "256"}},
+		{name: "aes-192-xts", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "384"}},
+		{name: "aes-256-xts", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "512"}},
+		{name: "xts with half a key", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "128"}, wantErr: true},
+		{name: "xts with too long key", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "1024"}, wantErr: true},
+		{name: "aes-cbc", options: &lhns.LuksFormatOptions{KeyCipher: "aes-cbc-essiv:sha256", KeySize: "192"}},
+		{name: "aes-cbc with xts key", options: &lhns.LuksFormatOptions{KeyCipher: "aes-cbc-essiv:sha256", KeySize: "512"}, wantErr: true},
+		{name: "key size not a multiple of 8", options: &lhns.LuksFormatOptions{KeySize: "100"}, wantErr: true},
+		{name: "key size not a number", options: &lhns.LuksFormatOptions{KeySize: "big"}, wantErr: true},
+		{name: "unknown hash", options: &lhns.LuksFormatOptions{KeyHash: "md5"}, wantErr: true},
+		{name: "unknown integrity", integrity: "crc32", wantErr: true},
+		{name: "aes-256-xts with hmac-sha256", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "768"}, integrity: IntegrityHMACSHA256},
+		{name: "aes-256-xts with hmac-sha512", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "1024"}, integrity: IntegrityHMACSHA512},
+		{name: "aes-128-xts with hmac-sha256", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "512"}, integrity: IntegrityHMACSHA256},
+		{name: "xts with hmac without hmac key", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "256"}, integrity: IntegrityHMACSHA256, wantErr: true},
+		{name: "key size only covering hmac", options: &lhns.LuksFormatOptions{KeySize: "512"}, integrity: IntegrityHMACSHA512, wantErr: true},
+		{name: "aes-cbc with hmac-sha256", options: &lhns.LuksFormatOptions{KeyCipher: "aes-cbc-essiv:sha256", KeySize: "512"}, integrity: IntegrityHMACSHA256},
+		{name: "aead", options: &lhns.LuksFormatOptions{KeyCipher: "aes-gcm-random"}, integrity: IntegrityAEAD},
+		{name: "aead without gcm", options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-random"}, integrity: IntegrityAEAD, wantErr: true},
+		{name: "poly1305", options: &lhns.LuksFormatOptions{KeyCipher: "chacha20-random"}, integrity: IntegrityPoly1305},
+		{name: "random IV without integrity", options: &lhns.LuksFormatOptions{KeyCipher: "aes-gcm-random"}, wantErr: true},
+		{name: "argon2 memory", options: &lhns.LuksFormatOptions{PBKDF: PBKDFArgon2id, PBKDFMemory: "65536"}},
+		{name: "pbkdf2 memory", options: &lhns.LuksFormatOptions{PBKDF: PBKDFPbkdf2, PBKDFMemory: "65536"}, wantErr: true},
+		{name: "argon2 memory too low", options: &lhns.LuksFormatOptions{PBKDFMemory: "16"}, wantErr: true},
+		{name: "argon2 iterations", options: &lhns.LuksFormatOptions{PBKDFForceIterations: "4"}},
+		{name: "argon2 iterations too low", options: &lhns.LuksFormatOptions{PBKDFForceIterations: "3"}, wantErr: true},
+		{name: "pbkdf2 iterations too low", options: &lhns.LuksFormatOptions{PBKDF: PBKDFPbkdf2, PBKDFForceIterations: "999"}, wantErr: true},
+		{name: "unknown pbkdf", options: &lhns.LuksFormatOptions{PBKDF: "scrypt"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLuksFormatOptions(tt.options, tt.integrity)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLuksFormatOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    *lhns.LuksFormatOptions
+		integrity  string
+		headerPath string
+		want       []string
+	}{
+		{
+			name: "defaults",
+			want: []string{"-q", "luksFormat", "--type", "luks2"},
+		},
+		{
+			name: "all options",
+			options: &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeyHash: "sha256", KeySize: "512",
+				PBKDF: PBKDFArgon2id, PBKDFForceIterations: "4", PBKDFMemory: "65536"},
+			want: []string{"-q", "luksFormat", "--type", "luks2", "--cipher", "aes-xts-plain64", "--hash", "sha256",
+				"--key-size", "512", "--pbkdf", "argon2id", "--pbkdf-force-iterations", "4", "--pbkdf-memory", "65536"},
+		},
+		{
+			name:       "integrity and detached header",
+			options:    &lhns.LuksFormatOptions{KeyCipher: "aes-xts-plain64", KeySize: "768"},
+			integrity:  IntegrityHMACSHA256,
+			headerPath: "/var/lib/longhorn/headers/vol",
+			want: []string{"-q", "luksFormat", "--type", "luks2", "--cipher", "aes-xts-plain64", "--key-size", "768",
+				"--integrity", "hmac-sha256", "--header", "/var/lib/longhorn/headers/vol", "--label", "vol"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := ParseLuksFormatOptions(tt.options, tt.integrity)
+			if err != nil {
+				t.Fatalf("ParseLuksFormatOptions() error = %v", err)
+			}
+			if got := params.FormatArgs(tt.headerPath, "vol"); !slices.Equal(got, tt.want) {
+				t.Errorf("FormatArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKernelHashName(t *testing.T) {
+	tests := []struct {
+		hash string
+		want string
+	}{
+		{hash: "sha256", want: "sha256"},
+		{hash: "ripemd160", want: "rmd160"},
+		{hash: "whirlpool", want: "wp512"},
+		{hash: "stribog256", want: "streebog256"},
+		{hash: "stribog512", want: "streebog512"},
+		{hash: "sm3", want: "sm3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hash, func(t *testing.T) {
+			if got := kernelHashName(tt.hash); got != tt.want {
+				t.Errorf("kernelHashName(%q) = %q, want %q", tt.hash, got, tt.want)
+			}
+		})
+	}
+}
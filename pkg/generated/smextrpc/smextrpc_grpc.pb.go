@@ -0,0 +1,808 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: smextrpc/smextrpc.proto
+
+// smextrpc are the RPCs of the share manager which are not part of the ShareManagerService of
+// github.com/longhorn/types yet. They move into smrpc once the messages land there.
+
+package smextrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ShareManagerExtensionService_GetEncryptionStatus_FullMethodName   = "/smextrpc.ShareManagerExtensionService/GetEncryptionStatus"
+	ShareManagerExtensionService_DestroyEncryption_FullMethodName     = "/smextrpc.ShareManagerExtensionService/DestroyEncryption"
+	ShareManagerExtensionService_StartFilesystemTrim_FullMethodName   = "/smextrpc.ShareManagerExtensionService/StartFilesystemTrim"
+	ShareManagerExtensionService_StartFilesystemResize_FullMethodName = "/smextrpc.ShareManagerExtensionService/StartFilesystemResize"
+	ShareManagerExtensionService_GetOperation_FullMethodName          = "/smextrpc.ShareManagerExtensionService/GetOperation"
+	ShareManagerExtensionService_ListOperations_FullMethodName        = "/smextrpc.ShareManagerExtensionService/ListOperations"
+	ShareManagerExtensionService_CancelOperation_FullMethodName       = "/smextrpc.ShareManagerExtensionService/CancelOperation"
+	ShareManagerExtensionService_WatchOperation_FullMethodName        = "/smextrpc.ShareManagerExtensionService/WatchOperation"
+	ShareManagerExtensionService_GetStatus_FullMethodName             = "/smextrpc.ShareManagerExtensionService/GetStatus"
+	ShareManagerExtensionService_ListClients_FullMethodName           = "/smextrpc.ShareManagerExtensionService/ListClients"
+	ShareManagerExtensionService_CreateSubExport_FullMethodName       = "/smextrpc.ShareManagerExtensionService/CreateSubExport"
+	ShareManagerExtensionService_ListSubExports_FullMethodName        = "/smextrpc.ShareManagerExtensionService/ListSubExports"
+	ShareManagerExtensionService_DeleteSubExport_FullMethodName       = "/smextrpc.ShareManagerExtensionService/DeleteSubExport"
+	ShareManagerExtensionService_SetDirectoryProject_FullMethodName   = "/smextrpc.ShareManagerExtensionService/SetDirectoryProject"
+	ShareManagerExtensionService_SetProjectQuota_FullMethodName       = "/smextrpc.ShareManagerExtensionService/SetProjectQuota"
+	ShareManagerExtensionService_GetProjectQuota_FullMethodName       = "/smextrpc.ShareManagerExtensionService/GetProjectQuota"
+	ShareManagerExtensionService_ExportSnapshot_FullMethodName        = "/smextrpc.ShareManagerExtensionService/ExportSnapshot"
+	ShareManagerExtensionService_ListSnapshotExports_FullMethodName   = "/smextrpc.ShareManagerExtensionService/ListSnapshotExports"
+	ShareManagerExtensionService_UnexportSnapshot_FullMethodName      = "/smextrpc.ShareManagerExtensionService/UnexportSnapshot"
+)
+
+// ShareManagerExtensionServiceClient is the client API for ShareManagerExtensionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ShareManagerExtensionServiceClient interface {
+	GetEncryptionStatus(ctx context.Context, in *GetEncryptionStatusRequest, opts ...grpc.CallOption) (*GetEncryptionStatusResponse, error)
+	DestroyEncryption(ctx context.Context, in *DestroyEncryptionRequest, opts ...grpc.CallOption) (*DestroyEncryptionResponse, error)
+	StartFilesystemTrim(ctx context.Context, in *StartFilesystemTrimRequest, opts ...grpc.CallOption) (*Operation, error)
+	StartFilesystemResize(ctx context.Context, in *StartFilesystemResizeRequest, opts ...grpc.CallOption) (*Operation, error)
+	GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*Operation, error)
+	ListOperations(ctx context.Context, in *ListOperationsRequest, opts ...grpc.CallOption) (*ListOperationsResponse, error)
+	CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*Operation, error)
+	WatchOperation(ctx context.Context, in *WatchOperationRequest, opts ...grpc.CallOption) (ShareManagerExtensionService_WatchOperationClient, error)
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	ListClients(ctx context.Context, in *ListClientsRequest, opts ...grpc.CallOption) (*ListClientsResponse, error)
+	CreateSubExport(ctx context.Context, in *CreateSubExportRequest, opts ...grpc.CallOption) (*SubExport, error)
+	ListSubExports(ctx context.Context, in *ListSubExportsRequest, opts ...grpc.CallOption) (*ListSubExportsResponse, error)
+	DeleteSubExport(ctx context.Context, in *DeleteSubExportRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SetDirectoryProject(ctx context.Context, in *SetDirectoryProjectRequest, opts ...grpc.CallOption) (*Operation, error)
+	SetProjectQuota(ctx context.Context, in *SetProjectQuotaRequest, opts ...grpc.CallOption) (*ProjectQuota, error)
+	GetProjectQuota(ctx context.Context, in *GetProjectQuotaRequest, opts ...grpc.CallOption) (*ProjectQuota, error)
+	ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*SnapshotExport, error)
+	ListSnapshotExports(ctx context.Context, in *ListSnapshotExportsRequest, opts ...grpc.CallOption) (*ListSnapshotExportsResponse, error)
+	UnexportSnapshot(ctx context.Context, in *UnexportSnapshotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type shareManagerExtensionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewShareManagerExtensionServiceClient(cc grpc.ClientConnInterface) ShareManagerExtensionServiceClient {
+	return &shareManagerExtensionServiceClient{cc}
+}
+
+func (c *shareManagerExtensionServiceClient) GetEncryptionStatus(ctx context.Context, in *GetEncryptionStatusRequest, opts ...grpc.CallOption) (*GetEncryptionStatusResponse, error) {
+	out := new(GetEncryptionStatusResponse)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_GetEncryptionStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) DestroyEncryption(ctx context.Context, in *DestroyEncryptionRequest, opts ...grpc.CallOption) (*DestroyEncryptionResponse, error) {
+	out := new(DestroyEncryptionResponse)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_DestroyEncryption_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) StartFilesystemTrim(ctx context.Context, in *StartFilesystemTrimRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_StartFilesystemTrim_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) StartFilesystemResize(ctx context.Context, in *StartFilesystemResizeRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_StartFilesystemResize_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) GetOperation(ctx context.Context, in *GetOperationRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_GetOperation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) ListOperations(ctx context.Context, in *ListOperationsRequest, opts ...grpc.CallOption) (*ListOperationsResponse, error) {
+	out := new(ListOperationsResponse)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_ListOperations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) CancelOperation(ctx context.Context, in *CancelOperationRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_CancelOperation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) WatchOperation(ctx context.Context, in *WatchOperationRequest, opts ...grpc.CallOption) (ShareManagerExtensionService_WatchOperationClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ShareManagerExtensionService_ServiceDesc.Streams[0], ShareManagerExtensionService_WatchOperation_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shareManagerExtensionServiceWatchOperationClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ShareManagerExtensionService_WatchOperationClient interface {
+	Recv() (*Operation, error)
+	grpc.ClientStream
+}
+
+type shareManagerExtensionServiceWatchOperationClient struct {
+	grpc.ClientStream
+}
+
+func (x *shareManagerExtensionServiceWatchOperationClient) Recv() (*Operation, error) {
+	m := new(Operation)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *shareManagerExtensionServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_GetStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) ListClients(ctx context.Context, in *ListClientsRequest, opts ...grpc.CallOption) (*ListClientsResponse, error) {
+	out := new(ListClientsResponse)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_ListClients_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) CreateSubExport(ctx context.Context, in *CreateSubExportRequest, opts ...grpc.CallOption) (*SubExport, error) {
+	out := new(SubExport)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_CreateSubExport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) ListSubExports(ctx context.Context, in *ListSubExportsRequest, opts ...grpc.CallOption) (*ListSubExportsResponse, error) {
+	out := new(ListSubExportsResponse)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_ListSubExports_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) DeleteSubExport(ctx context.Context, in *DeleteSubExportRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_DeleteSubExport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) SetDirectoryProject(ctx context.Context, in *SetDirectoryProjectRequest, opts ...grpc.CallOption) (*Operation, error) {
+	out := new(Operation)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_SetDirectoryProject_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) SetProjectQuota(ctx context.Context, in *SetProjectQuotaRequest, opts ...grpc.CallOption) (*ProjectQuota, error) {
+	out := new(ProjectQuota)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_SetProjectQuota_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) GetProjectQuota(ctx context.Context, in *GetProjectQuotaRequest, opts ...grpc.CallOption) (*ProjectQuota, error) {
+	out := new(ProjectQuota)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_GetProjectQuota_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*SnapshotExport, error) {
+	out := new(SnapshotExport)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_ExportSnapshot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) ListSnapshotExports(ctx context.Context, in *ListSnapshotExportsRequest, opts ...grpc.CallOption) (*ListSnapshotExportsResponse, error) {
+	out := new(ListSnapshotExportsResponse)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_ListSnapshotExports_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shareManagerExtensionServiceClient) UnexportSnapshot(ctx context.Context, in *UnexportSnapshotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ShareManagerExtensionService_UnexportSnapshot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ShareManagerExtensionServiceServer is the server API for ShareManagerExtensionService service.
+// All implementations must embed UnimplementedShareManagerExtensionServiceServer
+// for forward compatibility
+type ShareManagerExtensionServiceServer interface {
+	GetEncryptionStatus(context.Context, *GetEncryptionStatusRequest) (*GetEncryptionStatusResponse, error)
+	DestroyEncryption(context.Context, *DestroyEncryptionRequest) (*DestroyEncryptionResponse, error)
+	StartFilesystemTrim(context.Context, *StartFilesystemTrimRequest) (*Operation, error)
+	StartFilesystemResize(context.Context, *StartFilesystemResizeRequest) (*Operation, error)
+	GetOperation(context.Context, *GetOperationRequest) (*Operation, error)
+	ListOperations(context.Context, *ListOperationsRequest) (*ListOperationsResponse, error)
+	CancelOperation(context.Context, *CancelOperationRequest) (*Operation, error)
+	WatchOperation(*WatchOperationRequest, ShareManagerExtensionService_WatchOperationServer) error
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	ListClients(context.Context, *ListClientsRequest) (*ListClientsResponse, error)
+	CreateSubExport(context.Context, *CreateSubExportRequest) (*SubExport, error)
+	ListSubExports(context.Context, *ListSubExportsRequest) (*ListSubExportsResponse, error)
+	DeleteSubExport(context.Context, *DeleteSubExportRequest) (*emptypb.Empty, error)
+	SetDirectoryProject(context.Context, *SetDirectoryProjectRequest) (*Operation, error)
+	SetProjectQuota(context.Context, *SetProjectQuotaRequest) (*ProjectQuota, error)
+	GetProjectQuota(context.Context, *GetProjectQuotaRequest) (*ProjectQuota, error)
+	ExportSnapshot(context.Context, *ExportSnapshotRequest) (*SnapshotExport, error)
+	ListSnapshotExports(context.Context, *ListSnapshotExportsRequest) (*ListSnapshotExportsResponse, error)
+	UnexportSnapshot(context.Context, *UnexportSnapshotRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedShareManagerExtensionServiceServer()
+}
+
+// UnimplementedShareManagerExtensionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedShareManagerExtensionServiceServer struct {
+}
+
+func (UnimplementedShareManagerExtensionServiceServer) GetEncryptionStatus(context.Context, *GetEncryptionStatusRequest) (*GetEncryptionStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEncryptionStatus not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) DestroyEncryption(context.Context, *DestroyEncryptionRequest) (*DestroyEncryptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DestroyEncryption not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) StartFilesystemTrim(context.Context, *StartFilesystemTrimRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartFilesystemTrim not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) StartFilesystemResize(context.Context, *StartFilesystemResizeRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartFilesystemResize not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) GetOperation(context.Context, *GetOperationRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperation not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) ListOperations(context.Context, *ListOperationsRequest) (*ListOperationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOperations not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) CancelOperation(context.Context, *CancelOperationRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOperation not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) WatchOperation(*WatchOperationRequest, ShareManagerExtensionService_WatchOperationServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOperation not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) ListClients(context.Context, *ListClientsRequest) (*ListClientsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClients not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) CreateSubExport(context.Context, *CreateSubExportRequest) (*SubExport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSubExport not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) ListSubExports(context.Context, *ListSubExportsRequest) (*ListSubExportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubExports not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) DeleteSubExport(context.Context, *DeleteSubExportRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSubExport not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) SetDirectoryProject(context.Context, *SetDirectoryProjectRequest) (*Operation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDirectoryProject not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) SetProjectQuota(context.Context, *SetProjectQuotaRequest) (*ProjectQuota, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProjectQuota not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) GetProjectQuota(context.Context, *GetProjectQuotaRequest) (*ProjectQuota, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProjectQuota not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) ExportSnapshot(context.Context, *ExportSnapshotRequest) (*SnapshotExport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSnapshot not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) ListSnapshotExports(context.Context, *ListSnapshotExportsRequest) (*ListSnapshotExportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnapshotExports not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) UnexportSnapshot(context.Context, *UnexportSnapshotRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnexportSnapshot not implemented")
+}
+func (UnimplementedShareManagerExtensionServiceServer) mustEmbedUnimplementedShareManagerExtensionServiceServer() {
+}
+
+// UnsafeShareManagerExtensionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ShareManagerExtensionServiceServer will
+// result in compilation errors.
+type UnsafeShareManagerExtensionServiceServer interface {
+	mustEmbedUnimplementedShareManagerExtensionServiceServer()
+}
+
+func RegisterShareManagerExtensionServiceServer(s grpc.ServiceRegistrar, srv ShareManagerExtensionServiceServer) {
+	s.RegisterService(&ShareManagerExtensionService_ServiceDesc, srv)
+}
+
+func _ShareManagerExtensionService_GetEncryptionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEncryptionStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).GetEncryptionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_GetEncryptionStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).GetEncryptionStatus(ctx, req.(*GetEncryptionStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_DestroyEncryption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DestroyEncryptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).DestroyEncryption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_DestroyEncryption_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).DestroyEncryption(ctx, req.(*DestroyEncryptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_StartFilesystemTrim_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartFilesystemTrimRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).StartFilesystemTrim(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_StartFilesystemTrim_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).StartFilesystemTrim(ctx, req.(*StartFilesystemTrimRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_StartFilesystemResize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartFilesystemResizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).StartFilesystemResize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_StartFilesystemResize_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).StartFilesystemResize(ctx, req.(*StartFilesystemResizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_GetOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).GetOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_GetOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).GetOperation(ctx, req.(*GetOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_ListOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).ListOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_ListOperations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).ListOperations(ctx, req.(*ListOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_CancelOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).CancelOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_CancelOperation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).CancelOperation(ctx, req.(*CancelOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_WatchOperation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOperationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShareManagerExtensionServiceServer).WatchOperation(m, &shareManagerExtensionServiceWatchOperationServer{stream})
+}
+
+type ShareManagerExtensionService_WatchOperationServer interface {
+	Send(*Operation) error
+	grpc.ServerStream
+}
+
+type shareManagerExtensionServiceWatchOperationServer struct {
+	grpc.ServerStream
+}
+
+func (x *shareManagerExtensionServiceWatchOperationServer) Send(m *Operation) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ShareManagerExtensionService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_ListClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).ListClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_ListClients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).ListClients(ctx, req.(*ListClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_CreateSubExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSubExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).CreateSubExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_CreateSubExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).CreateSubExport(ctx, req.(*CreateSubExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_ListSubExports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubExportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).ListSubExports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_ListSubExports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).ListSubExports(ctx, req.(*ListSubExportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_DeleteSubExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSubExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).DeleteSubExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_DeleteSubExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).DeleteSubExport(ctx, req.(*DeleteSubExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_SetDirectoryProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDirectoryProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).SetDirectoryProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_SetDirectoryProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).SetDirectoryProject(ctx, req.(*SetDirectoryProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_SetProjectQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetProjectQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).SetProjectQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_SetProjectQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).SetProjectQuota(ctx, req.(*SetProjectQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_GetProjectQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).GetProjectQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_GetProjectQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).GetProjectQuota(ctx, req.(*GetProjectQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_ExportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).ExportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_ExportSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).ExportSnapshot(ctx, req.(*ExportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_ListSnapshotExports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSnapshotExportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).ListSnapshotExports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_ListSnapshotExports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).ListSnapshotExports(ctx, req.(*ListSnapshotExportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShareManagerExtensionService_UnexportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnexportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShareManagerExtensionServiceServer).UnexportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShareManagerExtensionService_UnexportSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShareManagerExtensionServiceServer).UnexportSnapshot(ctx, req.(*UnexportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ShareManagerExtensionService_ServiceDesc is the grpc.ServiceDesc for ShareManagerExtensionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ShareManagerExtensionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smextrpc.ShareManagerExtensionService",
+	HandlerType: (*ShareManagerExtensionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEncryptionStatus",
+			Handler:    _ShareManagerExtensionService_GetEncryptionStatus_Handler,
+		},
+		{
+			MethodName: "DestroyEncryption",
+			Handler:    _ShareManagerExtensionService_DestroyEncryption_Handler,
+		},
+		{
+			MethodName: "StartFilesystemTrim",
+			Handler:    _ShareManagerExtensionService_StartFilesystemTrim_Handler,
+		},
+		{
+			MethodName: "StartFilesystemResize",
+			Handler:    _ShareManagerExtensionService_StartFilesystemResize_Handler,
+		},
+		{
+			MethodName: "GetOperation",
+			Handler:    _ShareManagerExtensionService_GetOperation_Handler,
+		},
+		{
+			MethodName: "ListOperations",
+			Handler:    _ShareManagerExtensionService_ListOperations_Handler,
+		},
+		{
+			MethodName: "CancelOperation",
+			Handler:    _ShareManagerExtensionService_CancelOperation_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _ShareManagerExtensionService_GetStatus_Handler,
+		},
+		{
+			MethodName: "ListClients",
+			Handler:    _ShareManagerExtensionService_ListClients_Handler,
+		},
+		{
+			MethodName: "CreateSubExport",
+			Handler:    _ShareManagerExtensionService_CreateSubExport_Handler,
+		},
+		{
+			MethodName: "ListSubExports",
+			Handler:    _ShareManagerExtensionService_ListSubExports_Handler,
+		},
+		{
+			MethodName: "DeleteSubExport",
+			Handler:    _ShareManagerExtensionService_DeleteSubExport_Handler,
+		},
+		{
+			MethodName: "SetDirectoryProject",
+			Handler:    _ShareManagerExtensionService_SetDirectoryProject_Handler,
+		},
+		{
+			MethodName: "SetProjectQuota",
+			Handler:    _ShareManagerExtensionService_SetProjectQuota_Handler,
+		},
+		{
+			MethodName: "GetProjectQuota",
+			Handler:    _ShareManagerExtensionService_GetProjectQuota_Handler,
+		},
+		{
+			MethodName: "ExportSnapshot",
+			Handler:    _ShareManagerExtensionService_ExportSnapshot_Handler,
+		},
+		{
+			MethodName: "ListSnapshotExports",
+			Handler:    _ShareManagerExtensionService_ListSnapshotExports_Handler,
+		},
+		{
+			MethodName: "UnexportSnapshot",
+			Handler:    _ShareManagerExtensionService_UnexportSnapshot_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchOperation",
+			Handler:       _ShareManagerExtensionService_WatchOperation_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "smextrpc/smextrpc.proto",
+}
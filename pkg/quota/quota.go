@@ -0,0 +1,187 @@
+package quota
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// MountOption enables project quota accounting and enforcement on ext4 and xfs
+	MountOption = "prjquota"
+
+	// see linux/fs.h, the ioctls are the same on amd64 and arm64
+	fsIocFsGetXattr     = 0x801c581f
+	fsIocFsSetXattr     = 0x401c5820
+	fsXflagProjInherit  = 0x00000200
+	quotaBlockSize      = 1024 // dqb_*limit are in units of QIF_DQBLKSIZE
+	quotaSubCmdShift    = 8
+	quotaGetQuota       = 0x800007
+	quotaSetQuota       = 0x800008
+	quotaTypeProject    = 2
+	quotaValidBlkLimits = 1
+	quotaValidInoLimits = 4
+)
+
+var ErrNotEnabled = errors.New("project quotas are not enabled on the filesystem")
+
+// Quota are the limits and usage of a project. Block limits and usage are in bytes,
+// a limit of 0 means unlimited.
+type Quota struct {
+	BlockSoftLimit uint64
+	BlockHardLimit uint64
+	BlocksUsed     uint64
+	InodeSoftLimit uint64
+	InodeHardLimit uint64
+	InodesUsed     uint64
+}
+
+// fsxattr is struct fsxattr of linux/fs.h
+type fsxattr struct {
+	xflags     uint32
+	extsize    uint32
+	nextents   uint32
+	projid     uint32
+	cowextsize uint32
+	pad        [8]byte
+}
+
+// dqblk is struct if_dqblk of linux/quota.h
+type dqblk struct {
+	bhardlimit uint64
+	bsoftlimit uint64
+	curspace   uint64
+	ihardlimit uint64
+	isoftlimit uint64
+	curinodes  uint64
+	btime      uint64
+	itime      uint64
+	valid      uint32
+}
+
+// GetProjectID returns the project the file or directory is accounted to
+func GetProjectID(path string) (uint32, error) {
+	attr, err := getXattr(path)
+	if err != nil {
+		return 0, err
+	}
+	return attr.projid, nil
+}
+
+// SetProjectID assigns the directory and everything below it to the project and returns the
+// number of files and directories assigned. Directories inherit the project to new files.
+// Symlinks and special files are skipped, they cannot be opened to set the project. The walk
+// stops once ctx is cancelled, leaving the files visited so far assigned.
+func SetProjectID(ctx context.Context, path string, id uint32) (int, error) {
+	count := 0
+	err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() && !entry.IsDir() {
+			return nil
+		}
+
+		attr, err := getXattr(file)
+		if err != nil {
+			return err
+		}
+		attr.projid = id
+		if entry.IsDir() {
+			attr.xflags |= fsXflagProjInherit
+		}
+		if err := setXattr(file, attr); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// GetProjectQuota returns the limits and usage of the project on the filesystem of the device
+func GetProjectQuota(device string, id uint32) (*Quota, error) {
+	var dq dqblk
+	if err := quotactl(quotaGetQuota, device, id, &dq); err != nil {
+		return nil, err
+	}
+	return &Quota{
+		BlockSoftLimit: dq.bsoftlimit * quotaBlockSize,
+		BlockHardLimit: dq.bhardlimit * quotaBlockSize,
+		BlocksUsed:     dq.curspace,
+		InodeSoftLimit: dq.isoftlimit,
+		InodeHardLimit: dq.ihardlimit,
+		InodesUsed:     dq.curinodes,
+	}, nil
+}
+
+// SetProjectQuota sets the limits of the project, the usage fields are ignored.
+// Block limits are rounded up to KiB.
+func SetProjectQuota(device string, id uint32, limits Quota) error {
+	dq := dqblk{
+		bsoftlimit: (limits.BlockSoftLimit + quotaBlockSize - 1) / quotaBlockSize,
+		bhardlimit: (limits.BlockHardLimit + quotaBlockSize - 1) / quotaBlockSize,
+		isoftlimit: limits.InodeSoftLimit,
+		ihardlimit: limits.InodeHardLimit,
+		valid:      quotaValidBlkLimits | quotaValidInoLimits,
+	}
+	return quotactl(quotaSetQuota, device, id, &dq)
+}
+
+func quotactl(cmd int, device string, id uint32, dq *dqblk) error {
+	special, err := unix.BytePtrFromString(device)
+	if err != nil {
+		return err
+	}
+
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, uintptr(cmd<<quotaSubCmdShift|quotaTypeProject),
+		uintptr(unsafe.Pointer(special)), uintptr(id), uintptr(unsafe.Pointer(dq)), 0, 0)
+	switch errno {
+	case 0:
+		return nil
+	case unix.ESRCH, unix.ENOSYS:
+		return ErrNotEnabled
+	}
+	return errors.Wrapf(errno, "failed to access project quota %v on %v", id, device)
+}
+
+func getXattr(path string) (*fsxattr, error) {
+	var attr fsxattr
+	if err := ioctlXattr(path, fsIocFsGetXattr, &attr); err != nil {
+		return nil, errors.Wrapf(err, "failed to get project of %v", path)
+	}
+	return &attr, nil
+}
+
+func setXattr(path string, attr *fsxattr) error {
+	if err := ioctlXattr(path, fsIocFsSetXattr, attr); err != nil {
+		if errors.Is(err, unix.EOPNOTSUPP) {
+			return ErrNotEnabled
+		}
+		return errors.Wrapf(err, "failed to set project of %v", path)
+	}
+	return nil
+}
+
+func ioctlXattr(path string, request uintptr, attr *fsxattr) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), request, uintptr(unsafe.Pointer(attr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetProjectIDCancelled(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	count, err := SetProjectID(ctx, dir, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SetProjectID() error = %v, want %v", err, context.Canceled)
+	}
+	if count != 0 {
+		t.Errorf("SetProjectID() assigned %d entries after cancellation, want 0", count)
+	}
+}
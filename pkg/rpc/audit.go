@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/longhorn/types/pkg/generated/smrpc"
+	"google.golang.org/grpc"
+
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/audit"
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+)
+
+// mutatingMethods change the state of the share and are recorded in the audit log
+var mutatingMethods = []string{
+	smrpc.ShareManagerService_Mount_FullMethodName,
+	smrpc.ShareManagerService_Unmount_FullMethodName,
+	smrpc.ShareManagerService_FilesystemResize_FullMethodName,
+	smrpc.ShareManagerService_FilesystemTrim_FullMethodName,
+	smextrpc.ShareManagerExtensionService_DestroyEncryption_FullMethodName,
+	smextrpc.ShareManagerExtensionService_StartFilesystemTrim_FullMethodName,
+	smextrpc.ShareManagerExtensionService_StartFilesystemResize_FullMethodName,
+	smextrpc.ShareManagerExtensionService_CancelOperation_FullMethodName,
+	smextrpc.ShareManagerExtensionService_CreateSubExport_FullMethodName,
+	smextrpc.ShareManagerExtensionService_DeleteSubExport_FullMethodName,
+	smextrpc.ShareManagerExtensionService_ExportSnapshot_FullMethodName,
+	smextrpc.ShareManagerExtensionService_UnexportSnapshot_FullMethodName,
+	smextrpc.ShareManagerExtensionService_SetDirectoryProject_FullMethodName,
+	smextrpc.ShareManagerExtensionService_SetProjectQuota_FullMethodName,
+}
+
+// AuditUnaryServerInterceptor records every call of a mutating method in the audit log.
+// It has to be installed before the authorization interceptor, so denied calls are
+// recorded as well and the identities resolved by the authorizer are known.
+func AuditUnaryServerInterceptor(auditLogger *audit.Logger, volumeName string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !slices.Contains(mutatingMethods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		var resp interface{}
+		err := auditCall(ctx, auditLogger, volumeName, info.FullMethod, func(ctx context.Context) (err error) {
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
+
+// AuditStreamServerInterceptor is the streaming counterpart of AuditUnaryServerInterceptor,
+// a call is recorded once the stream ended.
+func AuditStreamServerInterceptor(auditLogger *audit.Logger, volumeName string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !slices.Contains(mutatingMethods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		return auditCall(ss.Context(), auditLogger, volumeName, info.FullMethod, func(ctx context.Context) error {
+			return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		})
+	}
+}
+
+func auditCall(ctx context.Context, auditLogger *audit.Logger, volumeName, fullMethod string, call func(ctx context.Context) error) error {
+	ctx, caller := withCaller(ctx)
+	start := time.Now()
+	err := call(ctx)
+
+	entry := audit.Entry{
+		Event:    audit.EventRPC,
+		Volume:   volumeName,
+		Method:   fullMethod,
+		Peer:     caller.Address,
+		Identity: caller.String(),
+	}
+	switch grpcstatus.Code(err) {
+	case grpccodes.PermissionDenied, grpccodes.Unauthenticated:
+		entry.Start, entry.End = start, time.Now()
+		entry.Outcome = audit.OutcomeDenied
+		entry.Error = err.Error()
+		auditLogger.Log(entry)
+	default:
+		auditLogger.LogResult(entry, start, err)
+	}
+	return err
+}
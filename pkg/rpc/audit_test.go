@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/longhorn/types/pkg/generated/smrpc"
+	"google.golang.org/grpc"
+
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/audit"
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestAuditStreamServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		err         error
+		wantOutcome string // empty if no entry is expected
+	}{
+		{name: "read-only method", method: smextrpc.ShareManagerExtensionService_WatchOperation_FullMethodName},
+		{name: "mutating method", method: smrpc.ShareManagerService_Unmount_FullMethodName, wantOutcome: audit.OutcomeSuccess},
+		{name: "failed", method: smrpc.ShareManagerService_Unmount_FullMethodName,
+			err: grpcstatus.Error(grpccodes.Internal, "busy"), wantOutcome: audit.OutcomeFailure},
+		{name: "denied", method: smrpc.ShareManagerService_Unmount_FullMethodName,
+			err: grpcstatus.Error(grpccodes.PermissionDenied, "denied"), wantOutcome: audit.OutcomeDenied},
+		{name: "unauthenticated", method: smrpc.ShareManagerService_Unmount_FullMethodName,
+			err: grpcstatus.Error(grpccodes.Unauthenticated, "plain text token"), wantOutcome: audit.OutcomeDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := filepath.Join(t.TempDir(), "audit.log")
+			auditLogger, err := audit.NewLogger(logFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			interceptor := AuditStreamServerInterceptor(auditLogger, "pvc-1234")
+			err = interceptor(nil, &fakeServerStream{}, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(srv interface{}, stream grpc.ServerStream) error { return tt.err })
+			if err != tt.err {
+				t.Fatalf("interceptor returned %v, want %v", err, tt.err)
+			}
+
+			content, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+			if tt.wantOutcome == "" {
+				if len(content) != 0 {
+					t.Errorf("unexpected audit entries %s", content)
+				}
+				return
+			}
+			if len(lines) != 1 {
+				t.Fatalf("got %d audit entries, want 1: %s", len(lines), content)
+			}
+
+			entry := audit.Entry{}
+			if err := json.Unmarshal(lines[0], &entry); err != nil {
+				t.Fatal(err)
+			}
+			if entry.Method != tt.method || entry.Volume != "pvc-1234" || entry.Outcome != tt.wantOutcome {
+				t.Errorf("audit entry = %+v, want method %v and outcome %v", entry, tt.method, tt.wantOutcome)
+			}
+		})
+	}
+}
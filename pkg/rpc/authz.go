@@ -0,0 +1,382 @@
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	grpccodes "google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+)
+
+const (
+	authorizationHeader = "authorization"
+	bearerPrefix        = "bearer "
+
+	allMethods = "*"
+)
+
+// openMethods only read state and can be called by everyone
+var openMethods = []string{
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+	healthpb.Health_List_FullMethodName,
+	smextrpc.ShareManagerExtensionService_GetEncryptionStatus_FullMethodName,
+	smextrpc.ShareManagerExtensionService_GetOperation_FullMethodName,
+	smextrpc.ShareManagerExtensionService_ListOperations_FullMethodName,
+	smextrpc.ShareManagerExtensionService_WatchOperation_FullMethodName,
+	smextrpc.ShareManagerExtensionService_GetStatus_FullMethodName,
+	smextrpc.ShareManagerExtensionService_ListClients_FullMethodName,
+	smextrpc.ShareManagerExtensionService_ListSubExports_FullMethodName,
+	smextrpc.ShareManagerExtensionService_ListSnapshotExports_FullMethodName,
+	smextrpc.ShareManagerExtensionService_GetProjectQuota_FullMethodName,
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+}
+
+type callerContextKey struct{}
+
+// Caller identifies the peer of an RPC.
+type Caller struct {
+	Address    string
+	Identities []string
+}
+
+// String returns the identities of the caller, or anonymous if there are none.
+func (c Caller) String() string {
+	if len(c.Identities) == 0 {
+		return "anonymous"
+	}
+	return strings.Join(c.Identities, ",")
+}
+
+// CallerFromContext returns the caller of the RPC. Its identities are only known
+// if the authorization interceptor ran before.
+func CallerFromContext(ctx context.Context) Caller {
+	if caller, ok := ctx.Value(callerContextKey{}).(*Caller); ok {
+		return *caller
+	}
+	caller := Caller{}
+	if p, ok := peer.FromContext(ctx); ok {
+		caller.Address = p.Addr.String()
+	}
+	return caller
+}
+
+// withCaller stores a caller in the context which later interceptors can fill in, so
+// an outer interceptor like the audit log sees identities resolved by an inner one.
+func withCaller(ctx context.Context) (context.Context, *Caller) {
+	if caller, ok := ctx.Value(callerContextKey{}).(*Caller); ok {
+		return ctx, caller
+	}
+	caller := CallerFromContext(ctx)
+	return context.WithValue(ctx, callerContextKey{}, &caller), &caller
+}
+
+// AuthzPolicy maps caller identities to the methods they may call. Sample policy file:
+//
+//	{
+//	  "rules": [
+//	    {"identity": "longhorn-manager.longhorn-system.svc", "methods": ["*"]},
+//	    {"identity": "trim-job", "methods": ["/ShareManagerService/FilesystemTrim"]},
+//	    {"identity": "snapshot-controller", "methods": ["/smextrpc.ShareManagerExtensionService/*"]}
+//	  ]
+//	}
+//
+// A method is either the full gRPC method name, where the leading slash may be left out,
+// /<service>/* for all methods of the service or * for all methods. Bare method names are rejected, they would match the methods of the same
+// name of every service.
+type AuthzPolicy struct {
+	Rules []AuthzRule `json:"rules"`
+}
+
+type AuthzRule struct {
+	Identity string   `json:"identity"`
+	Methods  []string `json:"methods"`
+}
+
+// normalize checks that every method of the policy is qualified by its service and adds the
+// leading slash of the full gRPC method name where it is missing
+func (p *AuthzPolicy) normalize() error {
+	for _, rule := range p.Rules {
+		for i, method := range rule.Methods {
+			if method == allMethods {
+				continue
+			}
+			service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+			if !ok || service == "" || name == "" || strings.Contains(name, "/") {
+				return errors.Errorf("method %q of identity %v must be %v, /<service>/<method> or /<service>/%v",
+					method, rule.Identity, allMethods, allMethods)
+			}
+			rule.Methods[i] = "/" + service + "/" + name
+		}
+	}
+	return nil
+}
+
+func (p *AuthzPolicy) allows(identity, fullMethod string) bool {
+	serviceMethods := fullMethod[:strings.LastIndex(fullMethod, "/")+1] + allMethods
+	for _, rule := range p.Rules {
+		if rule.Identity != identity {
+			continue
+		}
+		if slices.Contains(rule.Methods, allMethods) || slices.Contains(rule.Methods, fullMethod) || slices.Contains(rule.Methods, serviceMethods) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorizer is a gRPC interceptor which permits methods changing the share, such as
+// Mount, Unmount, FilesystemResize and FilesystemTrim, only to callers the policy
+// allows. Read-only methods like health checks stay open. A caller is identified by
+// the SANs of its verified client certificate, or by a bearer token listed in the
+// token file. Both files are reloaded when they change.
+type Authorizer struct {
+	logger logrus.FieldLogger
+
+	policyFile *watchedFile
+	tokenFile  *watchedFile
+
+	mutex  sync.Mutex
+	policy *AuthzPolicy
+	tokens map[string]string // token to identity
+}
+
+func NewAuthorizer(logger logrus.FieldLogger, policyFile, tokenFile string) (*Authorizer, error) {
+	a := &Authorizer{
+		logger:     logger,
+		policyFile: &watchedFile{path: policyFile},
+		policy:     &AuthzPolicy{},
+		tokens:     map[string]string{},
+	}
+	if tokenFile != "" {
+		a.tokenFile = &watchedFile{path: tokenFile}
+	}
+	if err := a.reload(true); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// reload reparses changed files, after the initial load a broken file keeps the previous content.
+func (a *Authorizer) reload(initial bool) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if content, changed, err := a.policyFile.read(); err != nil || changed {
+		policy := &AuthzPolicy{}
+		if err == nil {
+			err = json.Unmarshal(content, policy)
+		}
+		if err == nil {
+			err = policy.normalize()
+		}
+		if err != nil {
+			if initial {
+				return errors.Wrapf(err, "failed to load authorization policy %v", a.policyFile.path)
+			}
+			a.logger.WithError(err).Warnf("Failed to reload authorization policy %v, keeping the previous one", a.policyFile.path)
+		} else {
+			a.policy = policy
+		}
+	}
+
+	if a.tokenFile == nil {
+		return nil
+	}
+	if content, changed, err := a.tokenFile.read(); err != nil || changed {
+		tokens := map[string]string{}
+		if err == nil {
+			tokens, err = parseTokens(content)
+		}
+		if err != nil {
+			if initial {
+				return errors.Wrapf(err, "failed to load authorization tokens %v", a.tokenFile.path)
+			}
+			a.logger.WithError(err).Warnf("Failed to reload authorization tokens %v, keeping the previous ones", a.tokenFile.path)
+		} else {
+			a.tokens = tokens
+		}
+	}
+	return nil
+}
+
+// parseTokens parses lines of "<identity> <token>", empty lines and lines starting with # are skipped.
+func parseTokens(content []byte) (map[string]string, error) {
+	tokens := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, errors.Errorf("line %d is not in the format <identity> <token>", lineNumber)
+		}
+		tokens[fields[1]] = fields[0]
+	}
+	return tokens, scanner.Err()
+}
+
+// resolveIdentities returns the identities of the verified client certificate and of the
+// bearer tokens. Tokens are only accepted over TLS, a token sent in plain text is an error.
+func (a *Authorizer) resolveIdentities(ctx context.Context) ([]string, error) {
+	identities := []string{}
+
+	secure := false
+	if p, ok := peer.FromContext(ctx); ok {
+		tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+		secure = ok
+		if ok && len(tlsInfo.State.VerifiedChains) > 0 && len(tlsInfo.State.VerifiedChains[0]) > 0 {
+			cert := tlsInfo.State.VerifiedChains[0][0]
+			identities = append(identities, cert.DNSNames...)
+			for _, uri := range cert.URIs {
+				identities = append(identities, uri.String())
+			}
+			identities = append(identities, cert.EmailAddresses...)
+		}
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get(authorizationHeader) {
+			if len(value) <= len(bearerPrefix) || !strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+				continue
+			}
+			if !secure {
+				return nil, grpcstatus.Error(grpccodes.Unauthenticated, "bearer tokens are only accepted over TLS")
+			}
+			if identity := a.lookupToken(value[len(bearerPrefix):]); identity != "" {
+				identities = append(identities, identity)
+			}
+		}
+	}
+
+	return identities, nil
+}
+
+func (a *Authorizer) lookupToken(token string) string {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	// compare all tokens in constant time to not leak which prefix matched
+	identity := ""
+	for known, knownIdentity := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			identity = knownIdentity
+		}
+	}
+	return identity
+}
+
+func (a *Authorizer) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	if err := a.reload(false); err != nil {
+		a.logger.WithError(err).Warn("Failed to reload authorization files")
+	}
+
+	ctx, caller := withCaller(ctx)
+	identities, err := a.resolveIdentities(ctx)
+	if err != nil {
+		a.logger.WithFields(logrus.Fields{
+			"method":  fullMethod,
+			"peer":    caller.Address,
+			"granted": false,
+		}).WithError(err).Warn("Rejected share manager RPC")
+		return ctx, err
+	}
+	caller.Identities = identities
+
+	if slices.Contains(openMethods, fullMethod) {
+		return ctx, nil
+	}
+
+	a.mutex.Lock()
+	policy := a.policy
+	a.mutex.Unlock()
+
+	for _, identity := range caller.Identities {
+		if policy.allows(identity, fullMethod) {
+			return ctx, nil
+		}
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"method":  fullMethod,
+		"peer":    caller.Address,
+		"caller":  caller.String(),
+		"granted": false,
+	}).Warn("Denied share manager RPC")
+	return ctx, grpcstatus.Errorf(grpccodes.PermissionDenied, "caller %v is not allowed to call %v", caller, fullMethod)
+}
+
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextServerStream replaces the context of a server stream
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// watchedFile reads a file only when its modification time changed since the last read
+type watchedFile struct {
+	path    string
+	modTime time.Time
+}
+
+func (f *watchedFile) read() (content []byte, changed bool, err error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, false, err
+	}
+	if info.ModTime().Equal(f.modTime) {
+		return nil, false, nil
+	}
+
+	content, err = os.ReadFile(f.path)
+	if err != nil {
+		return nil, false, err
+	}
+	f.modTime = info.ModTime()
+	return content, true, nil
+}
@@ -0,0 +1,152 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+func TestParseTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "empty", content: "", want: map[string]string{}},
+		{
+			name:    "identities and tokens",
+			content: "longhorn-manager abc123\n  trim-job   def456  \n",
+			want:    map[string]string{"abc123": "longhorn-manager", "def456": "trim-job"},
+		},
+		{
+			name:    "comments and empty lines",
+			content: "# rotated monthly\n\nlonghorn-manager abc123\n",
+			want:    map[string]string{"abc123": "longhorn-manager"},
+		},
+		{name: "missing token", content: "longhorn-manager\n", wantErr: true},
+		{name: "too many fields", content: "longhorn-manager abc 123\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTokens([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTokens() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveIdentitiesRequiresTLSForTokens(t *testing.T) {
+	a := &Authorizer{
+		logger: logrus.New(),
+		tokens: map[string]string{"abc123": "longhorn-manager"},
+	}
+	addr := &net.TCPAddr{IP: net.ParseIP("10.42.0.5"), Port: 40000}
+
+	tests := []struct {
+		name     string
+		authInfo credentials.AuthInfo
+		header   string
+		want     []string
+		wantCode grpccodes.Code
+	}{
+		{name: "token over TLS", authInfo: credentials.TLSInfo{State: tls.ConnectionState{}}, header: "Bearer abc123", want: []string{"longhorn-manager"}},
+		{name: "unknown token over TLS", authInfo: credentials.TLSInfo{State: tls.ConnectionState{}}, header: "Bearer xyz", want: []string{}},
+		{name: "token in plain text", header: "Bearer abc123", wantCode: grpccodes.Unauthenticated},
+		{name: "no token in plain text", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr, AuthInfo: tt.authInfo})
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationHeader, tt.header))
+			}
+
+			got, err := a.resolveIdentities(ctx)
+			if code := grpcstatus.Code(err); code != tt.wantCode {
+				t.Fatalf("resolveIdentities() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveIdentities() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthzPolicyAllows(t *testing.T) {
+	policy := &AuthzPolicy{Rules: []AuthzRule{
+		{Identity: "admin", Methods: []string{"*"}},
+		{Identity: "trim-job", Methods: []string{"/ShareManagerService/FilesystemTrim"}},
+		{Identity: "snapshot-controller", Methods: []string{"/smextrpc.ShareManagerExtensionService/*"}},
+	}}
+
+	tests := []struct {
+		identity string
+		method   string
+		want     bool
+	}{
+		{identity: "admin", method: "/ShareManagerService/Unmount", want: true},
+		{identity: "trim-job", method: "/ShareManagerService/FilesystemTrim", want: true},
+		{identity: "trim-job", method: "/ShareManagerService/Unmount"},
+		{identity: "trim-job", method: "/other.Service/FilesystemTrim"},
+		{identity: "snapshot-controller", method: "/smextrpc.ShareManagerExtensionService/ExportSnapshot", want: true},
+		{identity: "snapshot-controller", method: "/ShareManagerService/Unmount"},
+		{identity: "unknown", method: "/ShareManagerService/FilesystemTrim"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.identity+tt.method, func(t *testing.T) {
+			if got := policy.allows(tt.identity, tt.method); got != tt.want {
+				t.Errorf("allows(%v, %v) = %v, want %v", tt.identity, tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthzPolicyNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		want    string
+		wantErr bool
+	}{
+		{name: "all methods", method: "*", want: "*"},
+		{name: "full method", method: "/ShareManagerService/Unmount", want: "/ShareManagerService/Unmount"},
+		{name: "missing leading slash", method: "ShareManagerService/Unmount", want: "/ShareManagerService/Unmount"},
+		{name: "all methods of a service", method: "/smextrpc.ShareManagerExtensionService/*", want: "/smextrpc.ShareManagerExtensionService/*"},
+		{name: "bare method", method: "Unmount", wantErr: true},
+		{name: "missing method", method: "/ShareManagerService/", wantErr: true},
+		{name: "missing service", method: "//Unmount", wantErr: true},
+		{name: "too many parts", method: "/ShareManagerService/Unmount/now", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &AuthzPolicy{Rules: []AuthzRule{{Identity: "caller", Methods: []string{tt.method}}}}
+			err := policy.normalize()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalize() with method %q error = %v, wantErr %v", tt.method, err, tt.wantErr)
+			}
+			if err == nil && policy.Rules[0].Methods[0] != tt.want {
+				t.Errorf("normalize() method %q = %q, want %q", tt.method, policy.Rules[0].Methods[0], tt.want)
+			}
+		})
+	}
+}
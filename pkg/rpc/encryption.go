@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"context"
+
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/crypto"
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+	"github.com/longhorn/longhorn-share-manager/pkg/volume"
+)
+
+func (s *ShareManagerServer) GetEncryptionStatus(ctx context.Context, req *smextrpc.GetEncryptionStatusRequest) (resp *smextrpc.GetEncryptionStatusResponse, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	vol := s.manager.GetVolume()
+	if vol.Name == "" {
+		s.logger.Warn("Volume name is missing")
+		return nil, grpcstatus.Error(grpccodes.FailedPrecondition, "volume name is missing")
+	}
+
+	log := s.logger.WithField("volume", vol.Name)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to get encryption status of volume")
+		}
+	}()
+
+	rawDevicePath := types.GetRawVolumeDevicePath(vol.Name)
+	if !volume.CheckDeviceValid(rawDevicePath) {
+		return nil, grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is not valid", vol.Name)
+	}
+
+	if err := checkLuksDevice(ctx, vol, rawDevicePath); err != nil {
+		return nil, err
+	}
+
+	headerLocation := rawDevicePath
+	if vol.CryptoHeaderPath != "" {
+		headerLocation = vol.CryptoHeaderPath
+	}
+
+	header, err := crypto.GetLuksHeader(ctx, headerLocation)
+	if err != nil {
+		return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
+	}
+
+	mode, err := crypto.GetDeviceMode(vol.Name, vol.DataEngine)
+	if err != nil {
+		return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
+	}
+
+	activeKeyslots := make([]int32, 0, len(header.ActiveKeyslots))
+	for _, keyslot := range header.ActiveKeyslots {
+		activeKeyslots = append(activeKeyslots, int32(keyslot))
+	}
+
+	return &smextrpc.GetEncryptionStatusResponse{
+		LuksVersion:    header.Version,
+		Cipher:         header.Cipher,
+		KeySize:        int32(header.KeySize),
+		Pbkdf:          header.PBKDF,
+		Hash:           header.Hash,
+		Integrity:      header.Integrity,
+		ActiveKeyslots: activeKeyslots,
+		SectorSize:     int32(header.SectorSize),
+		Offset:         header.Offset,
+		Mode:           mode,
+	}, nil
+}
+
+// DestroyEncryption crypto-shreds a decommissioned volume. It closes the crypto device,
+// erases all keyslots and overwrites the LUKS header, afterwards the data cannot be
+// recovered even with the passphrase. The volume has to be unmounted before, and the
+// request has to name the volume to confirm it targets the right share manager.
+func (s *ShareManagerServer) DestroyEncryption(ctx context.Context, req *smextrpc.DestroyEncryptionRequest) (resp *smextrpc.DestroyEncryptionResponse, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	vol := s.manager.GetVolume()
+	if vol.Name == "" {
+		s.logger.Warn("Volume name is missing")
+		return nil, grpcstatus.Error(grpccodes.FailedPrecondition, "volume name is missing")
+	}
+
+	log := s.logger.WithField("volume", vol.Name)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to destroy encryption of volume")
+		}
+	}()
+
+	if req.VolumeName != vol.Name {
+		return nil, grpcstatus.Errorf(grpccodes.InvalidArgument, "volume name %q does not match volume %v, refusing to destroy its encryption", req.VolumeName, vol.Name)
+	}
+
+	mountPath := types.GetMountPath(vol.Name)
+	if volume.CheckMountValid(mountPath) {
+		return nil, grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is still mounted at %v", vol.Name, mountPath)
+	}
+
+	rawDevicePath := types.GetRawVolumeDevicePath(vol.Name)
+	if !volume.CheckDeviceValid(rawDevicePath) {
+		return nil, grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is not valid", vol.Name)
+	}
+
+	if err := checkLuksDevice(ctx, vol, rawDevicePath); err != nil {
+		return nil, err
+	}
+
+	cryptoDevice := types.GetVolumeDevicePath(vol.Name, vol.DataEngine, true)
+	if isOpen, err := crypto.IsDeviceOpen(cryptoDevice); err != nil {
+		return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
+	} else if isOpen {
+		log.Infof("Closing crypto device %v", cryptoDevice)
+		if err := crypto.CloseVolume(ctx, vol.Name, vol.DataEngine); err != nil {
+			return nil, grpcstatus.Errorf(grpccodes.Internal, "failed to close crypto device %v: %v", cryptoDevice, err)
+		}
+	}
+
+	log.Warn("Destroying encryption of volume, the data will be unrecoverable")
+	header, err := crypto.EraseVolume(ctx, rawDevicePath, vol.CryptoHeaderPath)
+	if err != nil {
+		return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
+	}
+
+	erasedKeyslots := make([]int32, 0, len(header.ActiveKeyslots))
+	for _, keyslot := range header.ActiveKeyslots {
+		erasedKeyslots = append(erasedKeyslots, int32(keyslot))
+	}
+
+	log.Info("Destroyed encryption of volume")
+
+	return &smextrpc.DestroyEncryptionResponse{
+		ErasedKeyslots: erasedKeyslots,
+		WipedBytes:     header.Offset,
+	}, nil
+}
+
+// checkLuksDevice verifies the volume is LUKS encrypted, either by the signature on
+// the device or by the existence of its detached header.
+func checkLuksDevice(ctx context.Context, vol volume.Volume, rawDevicePath string) error {
+	if vol.CryptoHeaderPath != "" {
+		exists, err := crypto.DetachedHeaderExists(vol.CryptoHeaderPath)
+		if err != nil {
+			return grpcstatus.Error(grpccodes.Internal, err.Error())
+		}
+		if !exists {
+			return grpcstatus.Errorf(grpccodes.FailedPrecondition, "detached LUKS header %v of volume %v does not exist", vol.CryptoHeaderPath, vol.Name)
+		}
+		return nil
+	}
+
+	diskFormat, err := volume.GetDiskFormat(ctx, rawDevicePath)
+	if err != nil {
+		return grpcstatus.Errorf(grpccodes.Internal, "failed to determine disk format of volume %v: %v", vol.Name, err)
+	}
+	if diskFormat != "crypto_LUKS" {
+		return grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is not encrypted, disk format is %v", vol.Name, diskFormat)
+	}
+	return nil
+}
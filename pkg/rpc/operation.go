@@ -0,0 +1,307 @@
+package rpc
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+const (
+	// finished operations are kept so clients can still query the result after the start call timed out
+	maxFinishedOperations = 32
+
+	operationWatchInterval = 10 * time.Second
+)
+
+// operationFunc does the work of an operation, it should return early once ctx is cancelled.
+// The returned message is reported as the result of the operation.
+type operationFunc func(ctx context.Context, progress func(message string)) (string, error)
+
+type operation struct {
+	mutex  sync.Mutex
+	info   *smextrpc.Operation
+	key    string // what the operation works on, within its type
+	cancel context.CancelFunc
+	done   chan struct{}
+	update chan struct{} // closed and replaced on every change
+}
+
+func (o *operation) snapshot() *smextrpc.Operation {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return proto.Clone(o.info).(*smextrpc.Operation)
+}
+
+// changed returns a channel which is closed on the next change of the operation
+func (o *operation) changed() <-chan struct{} {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.update
+}
+
+func (o *operation) set(modify func(info *smextrpc.Operation)) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	modify(o.info)
+	close(o.update)
+	o.update = make(chan struct{})
+}
+
+// wait blocks until the operation is done or ctx is cancelled. The operation keeps running
+// in the latter case, so a client timing out does not abort a long trim.
+func (o *operation) wait(ctx context.Context) error {
+	select {
+	case <-o.done:
+	case <-ctx.Done():
+		return grpcstatus.FromContextError(ctx.Err()).Err()
+	}
+
+	info := o.snapshot()
+	switch info.State {
+	case types.OperationStateFailed:
+		return grpcstatus.Errorf(grpccodes.Internal, "%v operation %v failed: %v", info.Type, info.Id, info.Error)
+	case types.OperationStateCancelled:
+		return grpcstatus.Errorf(grpccodes.Canceled, "%v operation %v was cancelled", info.Type, info.Id)
+	}
+	return nil
+}
+
+// operationManager runs trim, resize and project assignments in the background. At most one
+// operation of each type runs at a time, starting the same one again returns the running operation.
+type operationManager struct {
+	logger logrus.FieldLogger
+
+	mutex      sync.Mutex
+	operations map[string]*operation
+	finished   []string // IDs in the order the operations finished
+	paused     bool     // set while the volume is being unmounted, no operation may start
+}
+
+func newOperationManager(logger logrus.FieldLogger) *operationManager {
+	return &operationManager{
+		logger:     logger,
+		operations: map[string]*operation{},
+	}
+}
+
+// start runs fn in the background. The context of the operation keeps the values of ctx,
+// such as the trace, but not its cancellation. The key tells apart operations of a type
+// working on different things, only one of them may run at a time.
+func (m *operationManager) start(ctx context.Context, opType, key string, fn operationFunc) (*operation, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.paused {
+		return nil, false, grpcstatus.Errorf(grpccodes.FailedPrecondition, "cannot start %v operation while the volume is unmounted", opType)
+	}
+
+	for _, op := range m.operations {
+		info := op.snapshot()
+		if info.Type != opType || types.IsOperationDone(info.State) {
+			continue
+		}
+		if op.key != key {
+			return nil, false, grpcstatus.Errorf(grpccodes.FailedPrecondition, "another %v operation %v is running", opType, info.Id)
+		}
+		return op, false, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	op := &operation{
+		info: &smextrpc.Operation{
+			Id:        uuid.NewString(),
+			Type:      opType,
+			State:     types.OperationStateRunning,
+			StartTime: timestamppb.Now(),
+		},
+		key:    key,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		update: make(chan struct{}),
+	}
+	m.operations[op.info.Id] = op
+
+	log := m.logger.WithFields(logrus.Fields{"operation": op.info.Id, "type": opType})
+	log.Info("Started operation")
+
+	go func() {
+		defer cancel()
+
+		message, err := fn(ctx, func(message string) {
+			op.set(func(info *smextrpc.Operation) { info.Message = message })
+		})
+
+		op.set(func(info *smextrpc.Operation) {
+			info.EndTime = timestamppb.Now()
+			if message != "" {
+				info.Message = message
+			}
+			switch {
+			case ctx.Err() != nil:
+				info.State = types.OperationStateCancelled
+			case err != nil:
+				info.State = types.OperationStateFailed
+				info.Error = err.Error()
+			default:
+				info.State = types.OperationStateSucceeded
+			}
+		})
+		close(op.done)
+
+		info := op.snapshot()
+		if err != nil && info.State == types.OperationStateFailed {
+			log.WithError(err).Errorf("Operation failed after %v", info.EndTime.AsTime().Sub(info.StartTime.AsTime()))
+		} else {
+			log.Infof("Operation %v after %v", info.State, info.EndTime.AsTime().Sub(info.StartTime.AsTime()))
+		}
+		m.retire(op.info.Id)
+	}()
+
+	return op, true, nil
+}
+
+// retire records a finished operation and forgets the oldest ones beyond the limit
+func (m *operationManager) retire(id string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.finished = append(m.finished, id)
+	for len(m.finished) > maxFinishedOperations {
+		delete(m.operations, m.finished[0])
+		m.finished = m.finished[1:]
+	}
+}
+
+func (m *operationManager) get(id string) (*operation, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	op, ok := m.operations[id]
+	if !ok {
+		return nil, grpcstatus.Errorf(grpccodes.NotFound, "operation %v not found", id)
+	}
+	return op, nil
+}
+
+// list returns all known operations, the oldest first
+func (m *operationManager) list() []*smextrpc.Operation {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	operations := make([]*smextrpc.Operation, 0, len(m.operations))
+	for _, op := range m.operations {
+		operations = append(operations, op.snapshot())
+	}
+	slices.SortFunc(operations, func(a, b *smextrpc.Operation) int {
+		return a.StartTime.AsTime().Compare(b.StartTime.AsTime())
+	})
+	return operations
+}
+
+// pause rejects new operations until resume is called, cancels the running ones and waits
+// for them to stop or ctx to be done.
+func (m *operationManager) pause(ctx context.Context) error {
+	m.mutex.Lock()
+	m.paused = true
+	running := []*operation{}
+	for _, op := range m.operations {
+		if !types.IsOperationDone(op.snapshot().State) {
+			running = append(running, op)
+		}
+	}
+	m.mutex.Unlock()
+
+	for _, op := range running {
+		m.logger.WithField("operation", op.info.Id).Info("Cancelling operation")
+		op.cancel()
+	}
+	for _, op := range running {
+		select {
+		case <-op.done:
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "failed to wait for %v operation %v to stop", op.info.Type, op.info.Id)
+		}
+	}
+	return nil
+}
+
+func (m *operationManager) resume() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.paused = false
+}
+
+func (s *ShareManagerServer) GetOperation(ctx context.Context, req *smextrpc.GetOperationRequest) (*smextrpc.Operation, error) {
+	op, err := s.operations.get(req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return op.snapshot(), nil
+}
+
+func (s *ShareManagerServer) ListOperations(ctx context.Context, req *smextrpc.ListOperationsRequest) (*smextrpc.ListOperationsResponse, error) {
+	return &smextrpc.ListOperationsResponse{Operations: s.operations.list()}, nil
+}
+
+// CancelOperation requests the operation to stop. A resize can only be cancelled
+// between its steps, the filesystem resize itself cannot be interrupted.
+func (s *ShareManagerServer) CancelOperation(ctx context.Context, req *smextrpc.CancelOperationRequest) (*smextrpc.Operation, error) {
+	op, err := s.operations.get(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	op.cancel()
+	select {
+	case <-op.done:
+	case <-ctx.Done():
+	}
+	return op.snapshot(), nil
+}
+
+// WatchOperation sends the operation on every change and periodically in between,
+// until it is done.
+func (s *ShareManagerServer) WatchOperation(req *smextrpc.WatchOperationRequest, stream smextrpc.ShareManagerExtensionService_WatchOperationServer) error {
+	op, err := s.operations.get(req.Id)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(operationWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		changed := op.changed()
+		info := op.snapshot()
+		if err := stream.Send(info); err != nil {
+			return err
+		}
+		if types.IsOperationDone(info.State) {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+func blockUntilCancelled(ctx context.Context, progress func(string)) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestOperationManagerPause(t *testing.T) {
+	m := newOperationManager(logrus.New())
+
+	op, started, err := m.start(context.Background(), types.OperationTypeTrim, "", blockUntilCancelled)
+	if err != nil || !started {
+		t.Fatalf("start() = %v, %v, want a started operation", started, err)
+	}
+
+	if _, started, err := m.start(context.Background(), types.OperationTypeTrim, "", blockUntilCancelled); err != nil || started {
+		t.Errorf("second start() = %v, %v, want the running operation", started, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.pause(ctx); err != nil {
+		t.Fatalf("pause() error = %v", err)
+	}
+	if state := op.snapshot().State; state != types.OperationStateCancelled {
+		t.Errorf("operation state after pause() = %v, want %v", state, types.OperationStateCancelled)
+	}
+
+	if _, _, err := m.start(context.Background(), types.OperationTypeResize, "", blockUntilCancelled); grpcstatus.Code(err) != grpccodes.FailedPrecondition {
+		t.Errorf("start() while paused error = %v, want code %v", err, grpccodes.FailedPrecondition)
+	}
+
+	m.resume()
+	resize, started, err := m.start(context.Background(), types.OperationTypeResize, "", blockUntilCancelled)
+	if err != nil || !started {
+		t.Fatalf("start() after resume() = %v, %v, want a started operation", started, err)
+	}
+	resize.cancel()
+	<-resize.done
+}
+
+func TestOperationManagerPauseWaits(t *testing.T) {
+	m := newOperationManager(logrus.New())
+
+	stopped := make(chan struct{})
+	if _, _, err := m.start(context.Background(), types.OperationTypeTrim, "", func(ctx context.Context, progress func(string)) (string, error) {
+		<-ctx.Done()
+		// the operation takes a while to stop after it was cancelled, like a killed fstrim
+		time.Sleep(50 * time.Millisecond)
+		close(stopped)
+		return "", ctx.Err()
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.pause(ctx); err != nil {
+		t.Fatalf("pause() error = %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("pause() returned before the operation stopped")
+	}
+}
+
+func TestOperationManagerPauseTimeout(t *testing.T) {
+	m := newOperationManager(logrus.New())
+
+	release := make(chan struct{})
+	defer close(release)
+	if _, _, err := m.start(context.Background(), types.OperationTypeResize, "", func(ctx context.Context, progress func(string)) (string, error) {
+		// a resize step cannot be interrupted
+		<-release
+		return "", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.pause(ctx); err == nil {
+		t.Error("pause() returned before the operation stopped")
+	}
+}
+
+func TestOperationManagerKey(t *testing.T) {
+	m := newOperationManager(logrus.New())
+
+	tests := []struct {
+		name        string
+		key         string
+		wantStarted bool
+		wantCode    grpccodes.Code
+	}{
+		{name: "first", key: "1:data", wantStarted: true},
+		{name: "same key", key: "1:data"},
+		{name: "other key", key: "2:data", wantCode: grpccodes.FailedPrecondition},
+	}
+
+	var first *operation
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, started, err := m.start(context.Background(), types.OperationTypeSetProject, tt.key, blockUntilCancelled)
+			if grpcstatus.Code(err) != tt.wantCode {
+				t.Fatalf("start() error = %v, want code %v", err, tt.wantCode)
+			}
+			if started != tt.wantStarted {
+				t.Errorf("start() started = %v, want %v", started, tt.wantStarted)
+			}
+			if first == nil {
+				first = op
+			} else if err == nil && op != first {
+				t.Errorf("start() returned operation %v, want the running %v", op.snapshot().Id, first.snapshot().Id)
+			}
+		})
+	}
+
+	first.cancel()
+	<-first.done
+}
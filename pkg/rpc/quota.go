@@ -0,0 +1,110 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+	"github.com/longhorn/longhorn-share-manager/pkg/quota"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+// SetDirectoryProject starts assigning a directory and everything below it to the project in the
+// background, or returns the assignment of the directory to the project which is already running
+func (s *ShareManagerServer) SetDirectoryProject(ctx context.Context, req *smextrpc.SetDirectoryProjectRequest) (op *smextrpc.Operation, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	log := s.logger.WithField("path", req.Path).WithField("projectID", req.ProjectId)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to start assigning directory to project")
+		}
+	}()
+
+	path, err := s.manager.CheckProjectDirectory(req.Path)
+	if err != nil {
+		return nil, managerError(err)
+	}
+
+	// the walk runs without the server lock, an unmount cancels it instead
+	key := fmt.Sprintf("%v:%v", req.ProjectId, path)
+	assignment, started, err := s.operations.start(ctx, types.OperationTypeSetProject, key, func(ctx context.Context, progress func(string)) (string, error) {
+		progress(fmt.Sprintf("assigning directory %v to project %v", path, req.ProjectId))
+		count, err := s.manager.SetDirectoryProject(ctx, path, req.ProjectId)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("assigned %v entries of directory %v to project %v", count, path, req.ProjectId), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if started {
+		log.Info("Assigning directory to project")
+	}
+
+	return assignment.snapshot(), nil
+}
+
+func (s *ShareManagerServer) SetProjectQuota(ctx context.Context, req *smextrpc.SetProjectQuotaRequest) (resp *smextrpc.ProjectQuota, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	log := s.logger.WithField("projectID", req.ProjectId)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to set project quota")
+		}
+	}()
+
+	projectQuota, err := s.manager.SetProjectQuota(ctx, req.ProjectId, quota.Quota{
+		BlockSoftLimit: req.BlockSoftLimit,
+		BlockHardLimit: req.BlockHardLimit,
+		InodeSoftLimit: req.InodeSoftLimit,
+		InodeHardLimit: req.InodeHardLimit,
+	})
+	if err != nil {
+		return nil, managerError(err)
+	}
+
+	return projectQuotaResponse(req.ProjectId, projectQuota), nil
+}
+
+func (s *ShareManagerServer) GetProjectQuota(ctx context.Context, req *smextrpc.GetProjectQuotaRequest) (*smextrpc.ProjectQuota, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	id, projectQuota, err := s.manager.GetProjectQuota(ctx, req.ProjectId, req.Path)
+	if err != nil {
+		return nil, managerError(err)
+	}
+
+	return projectQuotaResponse(id, projectQuota), nil
+}
+
+func projectQuotaResponse(id uint32, projectQuota *quota.Quota) *smextrpc.ProjectQuota {
+	return &smextrpc.ProjectQuota{
+		ProjectId:      id,
+		BlockSoftLimit: projectQuota.BlockSoftLimit,
+		BlockHardLimit: projectQuota.BlockHardLimit,
+		BlocksUsed:     projectQuota.BlocksUsed,
+		InodeSoftLimit: projectQuota.InodeSoftLimit,
+		InodeHardLimit: projectQuota.InodeHardLimit,
+		InodesUsed:     projectQuota.InodesUsed,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/google/fscrypt/filesystem"
 	"github.com/longhorn/types/pkg/generated/smrpc"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	grpccodes "google.golang.org/grpc/codes"
@@ -20,36 +22,44 @@ import (
 
 	"k8s.io/mount-utils"
 
-	lhexec "github.com/longhorn/go-common-libs/exec"
 	lhtypes "github.com/longhorn/go-common-libs/types"
 
 	"github.com/longhorn/longhorn-share-manager/pkg/crypto"
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
 	"github.com/longhorn/longhorn-share-manager/pkg/server"
-	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
 	"github.com/longhorn/longhorn-share-manager/pkg/types"
 	"github.com/longhorn/longhorn-share-manager/pkg/util"
 	"github.com/longhorn/longhorn-share-manager/pkg/volume"
 )
 
 const (
-	configPath = "/tmp/vfs.conf"
-
 	unmountRetryCount    = 30
 	unmountRetryInterval = 1
+
+	// trimTimeout bounds a trim started in the background, trimming a large volume can take hours
+	trimTimeout = 12 * time.Hour
+
+	// IntegrityHealthService is the health service name reporting the data integrity of the volume
+	IntegrityHealthService = "integrity"
 )
 
 type ShareManagerServer struct {
 	smrpc.UnimplementedShareManagerServiceServer
+	smextrpc.UnimplementedShareManagerExtensionServiceServer
 	sync.RWMutex
 
-	logger  logrus.FieldLogger
-	manager *server.ShareManager
+	logger     logrus.FieldLogger
+	manager    *server.ShareManager
+	operations *operationManager
 }
 
 func NewShareManagerServer(manager *server.ShareManager) *ShareManagerServer {
+	logger := util.NewLogger()
 	return &ShareManagerServer{
-		logger:  util.NewLogger(),
-		manager: manager,
+		logger:     logger,
+		manager:    manager,
+		operations: newOperationManager(logger),
 	}
 }
 
@@ -71,51 +81,125 @@ func (s *ShareManagerServer) FilesystemTrim(ctx context.Context, req *smrpc.File
 		}
 	}()
 
-	devicePath := types.GetVolumeDevicePath(vol.Name, vol.DataEngine, req.EncryptedDevice)
+	mountPath, err := checkFilesystemTrim(ctx, vol, req.EncryptedDevice)
+	if err != nil {
+		return &emptypb.Empty{}, err
+	}
+
+	log.Infof("Trimming mounted filesystem %v", mountPath)
+
+	if _, err = trimFilesystem(ctx, mountPath, lhtypes.ExecuteDefaultTimeout); err != nil {
+		return &emptypb.Empty{}, grpcstatus.Error(grpccodes.Internal, err.Error())
+	}
+
+	log.Infof("Finished trimming mounted filesystem %v", mountPath)
+
+	return &emptypb.Empty{}, nil
+}
+
+// StartFilesystemTrim starts trimming the mounted filesystem in the background, or returns
+// the trim which is already running.
+func (s *ShareManagerServer) StartFilesystemTrim(ctx context.Context, req *smextrpc.StartFilesystemTrimRequest) (op *smextrpc.Operation, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	vol := s.manager.GetVolume()
+	if vol.Name == "" {
+		return nil, grpcstatus.Error(grpccodes.FailedPrecondition, "volume name is missing")
+	}
+
+	log := s.logger.WithField("volume", vol.Name)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to start trimming mounted filesystem on volume")
+		}
+	}()
+
+	mountPath, err := checkFilesystemTrim(ctx, vol, req.EncryptedDevice)
+	if err != nil {
+		return nil, err
+	}
+
+	// the trim runs without the server lock, an unmount cancels it instead
+	trim, started, err := s.operations.start(ctx, types.OperationTypeTrim, "", func(ctx context.Context, progress func(string)) (string, error) {
+		progress(fmt.Sprintf("trimming %v", mountPath))
+		return trimFilesystem(ctx, mountPath, trimTimeout)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if started {
+		log.Infof("Trimming mounted filesystem %v", mountPath)
+	}
+
+	return trim.snapshot(), nil
+}
+
+// checkFilesystemTrim returns the mount path of the volume if it is mounted from the expected device
+func checkFilesystemTrim(ctx context.Context, vol volume.Volume, encryptedDevice bool) (string, error) {
+	devicePath := types.GetVolumeDevicePath(vol.Name, vol.DataEngine, encryptedDevice)
 	if !volume.CheckDeviceValid(devicePath) {
-		return &emptypb.Empty{}, grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is not valid", vol.Name)
+		return "", grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is not valid", vol.Name)
 	}
 
 	mountPath := types.GetMountPath(vol.Name)
 
 	mnt, err := filesystem.GetMount(mountPath)
 	if err != nil {
-		return &emptypb.Empty{}, grpcstatus.Error(grpccodes.Internal, err.Error())
+		return "", grpcstatus.Error(grpccodes.Internal, err.Error())
 	}
 
 	deviceNumber, err := util.GetDeviceNumber(devicePath)
 	if err != nil {
-		return &emptypb.Empty{}, grpcstatus.Error(grpccodes.Internal, err.Error())
+		return "", grpcstatus.Error(grpccodes.Internal, err.Error())
 	}
 
-	if uint64(mnt.DeviceNumber) != uint64(deviceNumber) {
-		return &emptypb.Empty{}, grpcstatus.Errorf(grpccodes.InvalidArgument, "the device of mount point %v is not expected", mountPath)
+	// btrfs reports an anonymous device number for its mounts, the mounted device has to be resolved
+	if mnt.FilesystemType == volume.FsTypeBtrfs {
+		if mountDeviceNumber, err := util.GetDeviceNumber(mnt.Device); err == nil {
+			mnt.DeviceNumber = filesystem.DeviceNumber(mountDeviceNumber)
+		}
 	}
 
-	log.Infof("Trimming mounted filesystem %v", mountPath)
+	if uint64(mnt.DeviceNumber) != uint64(deviceNumber) {
+		return "", grpcstatus.Errorf(grpccodes.InvalidArgument, "the device of mount point %v is not expected", mountPath)
+	}
 
-	mounter := mount.New("")
-	isMountPoint, err := mounter.IsMountPoint(mountPath)
+	isMountPoint, err := checkMountPoint(ctx, mountPath)
 	if !isMountPoint {
-		return &emptypb.Empty{}, grpcstatus.Errorf(grpccodes.InvalidArgument, "%v is not a mount point", mountPath)
+		return "", grpcstatus.Errorf(grpccodes.InvalidArgument, "%v is not a mount point", mountPath)
 	}
 	if err != nil {
-		return &emptypb.Empty{}, grpcstatus.Error(grpccodes.Internal, err.Error())
+		return "", grpcstatus.Error(grpccodes.Internal, err.Error())
 	}
 
 	if _, err := os.ReadDir(mountPath); err != nil {
-		return &emptypb.Empty{}, grpcstatus.Error(grpccodes.Internal, err.Error())
+		return "", grpcstatus.Error(grpccodes.Internal, err.Error())
 	}
 
-	execute := lhexec.NewExecutor().Execute
-	_, err = execute([]string{}, lhtypes.BinaryFstrim, []string{mountPath}, lhtypes.ExecuteDefaultTimeout)
+	return mountPath, nil
+}
+
+// trimFilesystem runs fstrim until it completes, the timeout expires or ctx is cancelled
+func trimFilesystem(ctx context.Context, mountPath string, timeout time.Duration) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "fstrim", attribute.String("mountPath", mountPath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, lhtypes.BinaryFstrim, "--verbose", mountPath).CombinedOutput()
 	if err != nil {
-		return &emptypb.Empty{}, grpcstatus.Error(grpccodes.Internal, err.Error())
+		if ctx.Err() == context.DeadlineExceeded {
+			err = errors.Wrapf(ctx.Err(), "fstrim did not finish within %v", timeout)
+		}
+		return "", errors.Wrapf(err, "failed to trim %v: %s", mountPath, strings.TrimSpace(string(output)))
 	}
 
-	log.Infof("Finished trimming mounted filesystem %v", mountPath)
-
-	return &emptypb.Empty{}, nil
+	return strings.TrimSpace(string(output)), nil
 }
 
 func (s *ShareManagerServer) FilesystemResize(ctx context.Context, req *emptypb.Empty) (resp *emptypb.Empty, err error) {
@@ -136,73 +220,149 @@ func (s *ShareManagerServer) FilesystemResize(ctx context.Context, req *emptypb.
 		}
 	}()
 
-	devicePath := types.GetVolumeDevicePath(vol.Name, vol.DataEngine, vol.IsEncrypted())
-	if !volume.CheckDeviceValid(devicePath) {
-		return &emptypb.Empty{}, grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is not valid", vol.Name)
+	devicePath, mountPath, err := checkFilesystemResize(ctx, vol, log)
+	if err != nil {
+		return &emptypb.Empty{}, err
 	}
-
-	mountPath := types.GetMountPath(vol.Name)
 	log = log.WithField("filesystem", mountPath)
 
-	_, err = filesystem.GetMount(mountPath)
+	log.Infof("Resizing mounted volume")
+
+	message, err := resizeFilesystem(ctx, vol, devicePath, mountPath, func(string) {})
 	if err != nil {
 		return &emptypb.Empty{}, grpcstatus.Error(grpccodes.Internal, err.Error())
 	}
 
-	log.Infof("Resizing mounted volume")
+	log.Info(message)
 
-	// Note that cryptsetup resize is only necessary for volumes resized while online.  For offline, it will happen automatically during 'open'.
-	if vol.IsEncrypted() {
+	return &emptypb.Empty{}, nil
+}
+
+// StartFilesystemResize starts growing the crypto device and filesystem to the size of the
+// volume in the background, or returns the resize which is already running.
+func (s *ShareManagerServer) StartFilesystemResize(ctx context.Context, req *smextrpc.StartFilesystemResizeRequest) (op *smextrpc.Operation, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	vol := s.manager.GetVolume()
+	if vol.Name == "" {
+		return nil, grpcstatus.Error(grpccodes.FailedPrecondition, "volume name is missing")
+	}
+
+	log := s.logger.WithField("volume", vol.Name)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to start resizing mounted filesystem on volume")
+		}
+	}()
+
+	devicePath, mountPath, err := checkFilesystemResize(ctx, vol, log)
+	if err != nil {
+		return nil, err
+	}
+
+	resize, started, err := s.operations.start(ctx, types.OperationTypeResize, "", func(ctx context.Context, progress func(string)) (string, error) {
+		// the resize holds the read lock, so a mount or unmount waits for it to finish
+		s.RLock()
+		defer s.RUnlock()
+
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return resizeFilesystem(ctx, vol, devicePath, mountPath, progress)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if started {
+		log.WithField("filesystem", mountPath).Infof("Resizing mounted volume")
+	}
+
+	return resize.snapshot(), nil
+}
+
+// checkFilesystemResize returns the device and mount path of the volume if its filesystem can be resized
+func checkFilesystemResize(ctx context.Context, vol volume.Volume, log logrus.FieldLogger) (string, string, error) {
+	devicePath := types.GetVolumeDevicePath(vol.Name, vol.DataEngine, vol.IsEncrypted())
+	if !volume.CheckDeviceValid(devicePath) {
+		return "", "", grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is not valid", vol.Name)
+	}
+
+	mountPath := types.GetMountPath(vol.Name)
+
+	if _, err := filesystem.GetMount(mountPath); err != nil {
+		return "", "", grpcstatus.Error(grpccodes.Internal, err.Error())
+	}
+
+	// a device with detached header carries no LUKS signature
+	if vol.IsEncrypted() && vol.CryptoHeaderPath == "" {
 		rawDevicePath := types.GetRawVolumeDevicePath(vol.Name)
-		diskFormat, err := volume.GetDiskFormat(rawDevicePath)
+		diskFormat, err := volume.GetDiskFormat(ctx, rawDevicePath)
 		if err != nil {
-			return &emptypb.Empty{}, grpcstatus.Errorf(grpccodes.Internal, "failed to determine disk format of volume %v: %v", vol.Name, err)
+			return "", "", grpcstatus.Errorf(grpccodes.Internal, "failed to determine disk format of volume %v: %v", vol.Name, err)
 		}
 		log.WithField("mappedDevice", devicePath).Infof("Encrypted volume device %v contains filesystem of format %v", rawDevicePath, diskFormat)
 
 		if diskFormat != "crypto_LUKS" {
-			return &emptypb.Empty{}, grpcstatus.Errorf(grpccodes.InvalidArgument, "unsupported disk encryption format %v", diskFormat)
+			return "", "", grpcstatus.Errorf(grpccodes.InvalidArgument, "unsupported disk encryption format %v", diskFormat)
 		}
+	}
+
+	return devicePath, mountPath, nil
+}
 
-		if err = crypto.ResizeEncryptoDevice(vol.Name, vol.DataEngine, vol.Passphrase); err != nil {
-			return &emptypb.Empty{}, grpcstatus.Errorf(grpccodes.Internal, "failed to resize crypto device %v for volume %v node expansion: %v", devicePath, vol.Name, err)
+// resizeFilesystem grows the crypto device and the filesystem. Cancellation is only
+// checked between the steps, since the tools cannot be interrupted safely.
+func resizeFilesystem(ctx context.Context, vol volume.Volume, devicePath, mountPath string, progress func(string)) (string, error) {
+	// Note that cryptsetup resize is only necessary for volumes resized while online.  For offline, it will happen automatically during 'open'.
+	if vol.IsEncrypted() {
+		progress(fmt.Sprintf("resizing crypto device %v", devicePath))
+		if err := crypto.ResizeEncryptoDevice(ctx, vol.Name, vol.DataEngine, vol.Passphrase, vol.CryptoHeaderPath); err != nil {
+			return "", errors.Wrapf(err, "failed to resize crypto device %v for volume %v node expansion", devicePath, vol.Name)
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
 		}
 	}
 
-	if resized, err := volume.ResizeVolume(devicePath, mountPath); err != nil {
-		log.WithError(err).Errorf("Failed to resize filesystem")
-		return &emptypb.Empty{}, grpcstatus.Error(grpccodes.Internal, err.Error())
-	} else if resized {
-		log.Infof("Resized filesystem")
-	} else {
-		log.Infof("No resize needed for filesystem")
+	progress(fmt.Sprintf("resizing filesystem %v", mountPath))
+	resized, err := volume.ResizeVolume(ctx, devicePath, mountPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resize filesystem %v", mountPath)
+	}
+	if !resized {
+		return "no resize needed for filesystem", nil
 	}
 
-	return &emptypb.Empty{}, nil
+	return "resized filesystem", nil
 }
 
-func (s *ShareManagerServer) unexport(vol volume.Volume) error {
-	exporter, err := nfs.NewExporter(configPath, types.ExportPath)
-	if err != nil {
-		return errors.Wrap(err, "failed to create nfs exporter")
+func (s *ShareManagerServer) unexport(ctx context.Context, vol volume.Volume) error {
+	if err := s.manager.UnexportSubExports(ctx); err != nil {
+		return errors.Wrap(err, "failed to delete nfs exports of sub-exports")
 	}
 
-	if err := exporter.DeleteExport(vol.Name); err != nil {
+	if err := s.manager.UnexportVolume(vol.Name); err != nil {
 		return errors.Wrap(err, "failed to delete nfs export")
 	}
 
-	if err := exporter.ReloadExport(); err != nil {
+	return s.reloadExport(ctx)
+}
+
+// reloadExport makes ganesha reread the export configuration with a SIGHUP
+func (s *ShareManagerServer) reloadExport(ctx context.Context) error {
+	if err := s.manager.ReloadExport(ctx); err != nil {
 		return errors.Wrap(err, "failed to reload nfs export")
 	}
 
 	return nil
 }
 
-func (s *ShareManagerServer) unmount(vol volume.Volume) error {
+func (s *ShareManagerServer) unmount(ctx context.Context, vol volume.Volume) error {
 	mountPath := types.GetMountPath(vol.Name)
 
-	mounter := mount.New("")
-	isMountPoint, err := mounter.IsMountPoint(mountPath)
+	isMountPoint, err := checkMountPoint(ctx, mountPath)
 	if err != nil {
 		return errors.Wrapf(err, "failed to check mount point %v", mountPath)
 	}
@@ -210,10 +370,28 @@ func (s *ShareManagerServer) unmount(vol volume.Volume) error {
 		return nil
 	}
 
-	return volume.UnmountVolume(mountPath)
+	return s.manager.UnmountVolume(ctx, mountPath)
+}
+
+func checkMountPoint(ctx context.Context, mountPath string) (isMountPoint bool, err error) {
+	_, span := tracing.Start(ctx, "volume.IsMountPoint", attribute.String("mountPath", mountPath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	return mount.New("").IsMountPoint(mountPath)
 }
 
 func (s *ShareManagerServer) Unmount(ctx context.Context, req *emptypb.Empty) (resp *emptypb.Empty, err error) {
+	// a running operation would keep the mount busy. The running ones are cancelled and waited
+	// for before taking the lock, since a resize holds the read lock until it finishes, and no
+	// new one starts until the unmount is done. A resize step cannot be interrupted, if it does
+	// not finish before ctx is done the unmount fails.
+	defer s.operations.resume()
+	if err := s.operations.pause(ctx); err != nil {
+		return nil, grpcstatus.Error(grpcstatus.FromContextError(ctx.Err()).Code(), err.Error())
+	}
+
 	s.Lock()
 	defer s.Unlock()
 
@@ -241,14 +419,19 @@ func (s *ShareManagerServer) Unmount(ctx context.Context, req *emptypb.Empty) (r
 	}()
 
 	log.Info("Unexporting volume")
-	err = s.unexport(vol)
+	err = s.unexport(ctx, vol)
 	if err != nil {
 		return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
 	}
 
+	// the snapshots are mounted below the volume
+	if err = s.manager.ReleaseSnapshots(ctx); err != nil {
+		return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
+	}
+
 	log.Info("Unmounting volume")
 	for i := 0; i < unmountRetryCount; i++ {
-		err = s.unmount(vol)
+		err = s.unmount(ctx, vol)
 		if err != nil && strings.Contains(err.Error(), "target is busy") {
 			time.Sleep(unmountRetryInterval)
 			continue
@@ -264,29 +447,24 @@ func (s *ShareManagerServer) Unmount(ctx context.Context, req *emptypb.Empty) (r
 	return &emptypb.Empty{}, nil
 }
 
-func (s *ShareManagerServer) mount(vol volume.Volume, devicePath, mountPath string) error {
-	if err := s.manager.MountVolume(s.manager.GetVolume(), devicePath, mountPath); err != nil {
+func (s *ShareManagerServer) mount(ctx context.Context, vol volume.Volume, devicePath, mountPath string) error {
+	if _, err := s.manager.MountVolume(ctx, s.manager.GetVolume(), devicePath, mountPath); err != nil {
 		return errors.Wrapf(err, "failed to mount volume %v", vol.Name)
 	}
 
 	return nil
 }
 
-func (s *ShareManagerServer) export(vol volume.Volume) error {
-	exporter, err := nfs.NewExporter(configPath, types.ExportPath)
-	if err != nil {
-		return errors.Wrap(err, "failed to create nfs exporter")
+func (s *ShareManagerServer) export(ctx context.Context, vol volume.Volume) error {
+	if err := s.manager.ExportVolume(vol.Name); err != nil {
+		return errors.Wrap(err, "failed to create nfs export")
 	}
 
-	if _, err := exporter.CreateExport(vol.Name); err != nil {
-		return errors.Wrap(err, "failed to delete nfs export")
+	if err := s.manager.ExportSubExports(ctx); err != nil {
+		s.logger.WithError(err).Error("Failed to create nfs exports of sub-exports")
 	}
 
-	if err := exporter.ReloadExport(); err != nil {
-		return errors.Wrap(err, "failed to reload nfs export")
-	}
-
-	return nil
+	return s.reloadExport(ctx)
 }
 
 func (s *ShareManagerServer) Mount(ctx context.Context, req *emptypb.Empty) (resp *emptypb.Empty, err error) {
@@ -321,22 +499,21 @@ func (s *ShareManagerServer) Mount(ctx context.Context, req *emptypb.Empty) (res
 		}
 	}()
 
-	mounter := mount.New("")
-	isMountPoint, err := mounter.IsMountPoint(mountPath)
+	isMountPoint, err := checkMountPoint(ctx, mountPath)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to check mount point %v", mountPath)
 		return &emptypb.Empty{}, grpcstatus.Errorf(grpccodes.Internal, "%v", err)
 	}
 	if !isMountPoint {
 		log.Info("Mounting volume")
-		err = s.mount(vol, devicePath, mountPath)
+		err = s.mount(ctx, vol, devicePath, mountPath)
 		if err != nil {
 			return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
 		}
 	}
 
 	log.Info("Exporting volume")
-	err = s.export(vol)
+	err = s.export(ctx, vol)
 	if err != nil {
 		return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
 	}
@@ -357,7 +534,11 @@ func NewShareManagerHealthCheckServer(srv *ShareManagerServer) *ShareManagerHeal
 	}
 }
 
-func (s *ShareManagerHealthCheckServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+func (s *ShareManagerHealthCheckServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() == IntegrityHealthService {
+		return s.checkIntegrity()
+	}
+
 	if s.isServing() {
 		return &healthpb.HealthCheckResponse{
 			Status: healthpb.HealthCheckResponse_SERVING,
@@ -371,7 +552,7 @@ func (s *ShareManagerHealthCheckServer) Check(context.Context, *healthpb.HealthC
 
 func (s *ShareManagerHealthCheckServer) Watch(req *healthpb.HealthCheckRequest, ws healthpb.Health_WatchServer) error {
 	for {
-		if s.srv != nil {
+		if s.isServing() {
 			if err := ws.Send(&healthpb.HealthCheckResponse{
 				Status: healthpb.HealthCheckResponse_SERVING,
 			}); err != nil {
@@ -387,17 +568,48 @@ func (s *ShareManagerHealthCheckServer) Watch(req *healthpb.HealthCheckRequest,
 			}
 
 		}
-		time.Sleep(time.Second)
+
+		select {
+		case <-ws.Context().Done():
+			return ws.Context().Err()
+		case <-time.After(time.Second):
+		}
 	}
 }
 
 func (s *ShareManagerHealthCheckServer) List(context.Context, *healthpb.HealthListRequest) (*healthpb.HealthListResponse, error) {
-	return &healthpb.HealthListResponse{
-		Statuses: map[string]*healthpb.HealthCheckResponse{
-			"grpc": {
-				Status: healthpb.HealthCheckResponse_SERVING,
-			},
+	statuses := map[string]*healthpb.HealthCheckResponse{
+		"grpc": {
+			Status: healthpb.HealthCheckResponse_SERVING,
 		},
+	}
+
+	if resp, err := s.checkIntegrity(); err == nil {
+		statuses[IntegrityHealthService] = resp
+	}
+
+	return &healthpb.HealthListResponse{
+		Statuses: statuses,
+	}, nil
+}
+
+// checkIntegrity reports integrity mismatches detected by dm-integrity as a health state
+// distinct from the serving state, since the share is still exported when they occur.
+func (s *ShareManagerHealthCheckServer) checkIntegrity() (*healthpb.HealthCheckResponse, error) {
+	if s.srv == nil || s.srv.manager == nil || !s.srv.manager.HasIntegrityProtection() {
+		return &healthpb.HealthCheckResponse{
+			Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN,
+		}, grpcstatus.Errorf(grpccodes.NotFound, "volume has no integrity protection")
+	}
+
+	if s.srv.manager.HasIntegrityErrors() {
+		return &healthpb.HealthCheckResponse{
+			Status: healthpb.HealthCheckResponse_NOT_SERVING,
+		}, nil
+	}
+
+	return &healthpb.HealthCheckResponse{
+		Status: healthpb.HealthCheckResponse_SERVING,
 	}, nil
 }
 
@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+	"github.com/longhorn/longhorn-share-manager/pkg/server"
+	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+)
+
+func (s *ShareManagerServer) ExportSnapshot(ctx context.Context, req *smextrpc.ExportSnapshotRequest) (resp *smextrpc.SnapshotExport, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	log := s.logger.WithFields(logrus.Fields{"snapshot": req.Snapshot.Name, "devicePath": req.Snapshot.DevicePath})
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to export snapshot")
+		}
+	}()
+
+	snapshot, err := s.manager.ExportSnapshot(ctx, server.SnapshotExport{
+		Name:       req.Snapshot.Name,
+		DevicePath: req.Snapshot.DevicePath,
+		ExportOptions: nfs.ExportOptions{
+			Squash:  req.Snapshot.Squash,
+			Clients: req.Snapshot.Clients,
+		},
+	})
+	if err != nil {
+		return nil, managerError(err)
+	}
+
+	return s.snapshotExportResponse(snapshot), nil
+}
+
+func (s *ShareManagerServer) ListSnapshotExports(ctx context.Context, req *smextrpc.ListSnapshotExportsRequest) (*smextrpc.ListSnapshotExportsResponse, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	snapshots := s.manager.ListSnapshotExports()
+	resp := &smextrpc.ListSnapshotExportsResponse{Snapshots: make([]*smextrpc.SnapshotExport, 0, len(snapshots))}
+	for _, snapshot := range snapshots {
+		resp.Snapshots = append(resp.Snapshots, s.snapshotExportResponse(snapshot))
+	}
+	return resp, nil
+}
+
+func (s *ShareManagerServer) UnexportSnapshot(ctx context.Context, req *smextrpc.UnexportSnapshotRequest) (resp *emptypb.Empty, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	log := s.logger.WithField("snapshot", req.Name)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to unexport snapshot")
+		}
+	}()
+
+	if err := s.manager.UnexportSnapshot(ctx, req.Name); err != nil {
+		return nil, managerError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *ShareManagerServer) snapshotExportResponse(snapshot server.SnapshotExport) *smextrpc.SnapshotExport {
+	vol := s.manager.GetVolume()
+	return &smextrpc.SnapshotExport{
+		Name:       snapshot.Name,
+		DevicePath: snapshot.DevicePath,
+		Squash:     snapshot.Squash,
+		Clients:    snapshot.Clients,
+		Pseudo:     nfs.SnapshotPseudoPath(vol.Name, snapshot.Name),
+		FsType:     snapshot.FsType,
+		Encrypted:  snapshot.Encrypted,
+		ExportId:   int32(s.manager.GetSnapshotExportID(snapshot.Name)),
+	}
+}
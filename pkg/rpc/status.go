@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+func (s *ShareManagerServer) GetStatus(ctx context.Context, req *smextrpc.GetStatusRequest) (*smextrpc.GetStatusResponse, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	vol := s.manager.GetVolume()
+	if vol.Name == "" {
+		s.logger.Warn("Volume name is missing")
+		return nil, grpcstatus.Error(grpccodes.FailedPrecondition, "volume name is missing")
+	}
+
+	mountPath := types.GetMountPath(vol.Name)
+	mounted, err := checkMountPoint(ctx, mountPath)
+	if err != nil {
+		s.logger.WithError(err).Warnf("Failed to check mount point %v", mountPath)
+	}
+
+	ports := []*smextrpc.NFSServicePort{}
+	for _, port := range s.manager.GetNFSPorts() {
+		ports = append(ports, &smextrpc.NFSServicePort{Service: port.Service, Port: int32(port.Port)})
+	}
+
+	return &smextrpc.GetStatusResponse{
+		Volume:             vol.Name,
+		DataEngine:         vol.DataEngine,
+		FsType:             vol.FsType,
+		Encrypted:          vol.IsEncrypted(),
+		DevicePath:         types.GetVolumeDevicePath(vol.Name, vol.DataEngine, vol.IsEncrypted()),
+		MountPath:          mountPath,
+		Mounted:            mounted,
+		Exported:           s.manager.ShareIsExported(),
+		NfsServerRunning:   nfsServerIsRunning(),
+		Serving:            s.manager.IsServing(),
+		IntegrityProtected: s.manager.HasIntegrityProtection(),
+		IntegrityErrors:    s.manager.HasIntegrityErrors(),
+		ProjectQuota:       s.manager.ProjectQuotaEnabled(),
+		Ports:              ports,
+	}, nil
+}
+
+func (s *ShareManagerServer) ListClients(ctx context.Context, req *smextrpc.ListClientsRequest) (*smextrpc.ListClientsResponse, error) {
+	clients, err := nfs.ListClients()
+	if err != nil {
+		return nil, grpcstatus.Errorf(grpccodes.Internal, "failed to list nfs clients: %v", err)
+	}
+
+	resp := &smextrpc.ListClientsResponse{Clients: make([]*smextrpc.NFSClient, 0, len(clients))}
+	for _, client := range clients {
+		resp.Clients = append(resp.Clients, &smextrpc.NFSClient{
+			Address:     client.Address,
+			Connections: int32(client.Connections),
+		})
+	}
+	return resp, nil
+}
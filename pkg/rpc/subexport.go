@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/generated/smextrpc"
+	"github.com/longhorn/longhorn-share-manager/pkg/quota"
+	"github.com/longhorn/longhorn-share-manager/pkg/server"
+	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+)
+
+func (s *ShareManagerServer) CreateSubExport(ctx context.Context, req *smextrpc.CreateSubExportRequest) (resp *smextrpc.SubExport, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	log := s.logger.WithField("subExport", req.SubExport.Name)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to create sub-export")
+		}
+	}()
+
+	subExport, err := s.manager.CreateSubExport(ctx, nfs.SubExport{
+		Name:   req.SubExport.Name,
+		Path:   req.SubExport.Path,
+		Pseudo: req.SubExport.Pseudo,
+		ExportOptions: nfs.ExportOptions{
+			AccessType: req.SubExport.AccessType,
+			Squash:     req.SubExport.Squash,
+			Clients:    req.SubExport.Clients,
+		},
+	})
+	if err != nil {
+		return nil, managerError(err)
+	}
+
+	return s.subExportResponse(subExport), nil
+}
+
+func (s *ShareManagerServer) ListSubExports(ctx context.Context, req *smextrpc.ListSubExportsRequest) (*smextrpc.ListSubExportsResponse, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	subExports, err := s.manager.ListSubExports()
+	if err != nil {
+		return nil, grpcstatus.Error(grpccodes.Internal, err.Error())
+	}
+
+	resp := &smextrpc.ListSubExportsResponse{SubExports: make([]*smextrpc.SubExport, 0, len(subExports))}
+	for _, subExport := range subExports {
+		resp.SubExports = append(resp.SubExports, s.subExportResponse(subExport))
+	}
+	return resp, nil
+}
+
+func (s *ShareManagerServer) DeleteSubExport(ctx context.Context, req *smextrpc.DeleteSubExportRequest) (resp *emptypb.Empty, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.checkShareExported(); err != nil {
+		return nil, err
+	}
+
+	log := s.logger.WithField("subExport", req.Name)
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Errorf("Failed to delete sub-export")
+		}
+	}()
+
+	if err := s.manager.DeleteSubExport(ctx, req.Name); err != nil {
+		return nil, managerError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// checkShareExported makes sure the volume is mounted and exported
+func (s *ShareManagerServer) checkShareExported() error {
+	vol := s.manager.GetVolume()
+	if vol.Name == "" {
+		s.logger.Warn("Volume name is missing")
+		return grpcstatus.Error(grpccodes.FailedPrecondition, "volume name is missing")
+	}
+	if !s.manager.ShareIsExported() {
+		return grpcstatus.Errorf(grpccodes.FailedPrecondition, "volume %v is not exported", vol.Name)
+	}
+	return nil
+}
+
+func (s *ShareManagerServer) subExportResponse(subExport nfs.SubExport) *smextrpc.SubExport {
+	return &smextrpc.SubExport{
+		Name:       subExport.Name,
+		Path:       subExport.Path,
+		Pseudo:     subExport.Pseudo,
+		AccessType: subExport.AccessType,
+		Squash:     subExport.Squash,
+		Clients:    subExport.Clients,
+		ExportId:   int32(s.manager.GetSubExportID(subExport.Name)),
+	}
+}
+
+// managerError maps the errors of the share manager to gRPC status codes
+func managerError(err error) error {
+	switch {
+	case errors.Is(err, server.ErrInvalidSubExport), errors.Is(err, server.ErrInvalidPath), errors.Is(err, server.ErrInvalidProject),
+		errors.Is(err, server.ErrInvalidSnapshot):
+		return grpcstatus.Error(grpccodes.InvalidArgument, err.Error())
+	case errors.Is(err, server.ErrSubExportExists), errors.Is(err, server.ErrSnapshotExported):
+		return grpcstatus.Error(grpccodes.AlreadyExists, err.Error())
+	case errors.Is(err, server.ErrSubExportNotFound), errors.Is(err, server.ErrSnapshotNotExported):
+		return grpcstatus.Error(grpccodes.NotFound, err.Error())
+	case errors.Is(err, server.ErrProjectQuotaDisabled), errors.Is(err, quota.ErrNotEnabled):
+		return grpcstatus.Error(grpccodes.FailedPrecondition, err.Error())
+	}
+	return grpcstatus.Error(grpccodes.Internal, err.Error())
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+	"slices"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/volume"
+)
+
+// btrfsSubvolumeOptions adds the option mounting the configured subvolume of a btrfs filesystem.
+// The subvolume is created on first use, for which the top level is mounted briefly, formatting
+// a new device on the way.
+func (m *ShareManager) btrfsSubvolumeOptions(ctx context.Context, subvolume, devicePath, mountPath string,
+	mountOptions, formatOptions []string) ([]string, error) {
+	if !volume.CheckMountValid(mountPath) {
+		if err := volume.MountVolume(ctx, devicePath, mountPath, volume.FsTypeBtrfs, mountOptions, formatOptions); err != nil {
+			return nil, errors.Wrap(err, "failed to mount top level of btrfs filesystem")
+		}
+		created, err := volume.CreateBtrfsSubvolume(ctx, mountPath, subvolume)
+		if errUnmount := volume.UnmountVolume(ctx, mountPath); errUnmount != nil {
+			return nil, errors.CombineErrors(err, errors.Wrap(errUnmount, "failed to unmount top level of btrfs filesystem"))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			m.logger.Infof("Created btrfs subvolume %v", subvolume)
+		}
+	}
+
+	return append(slices.Clone(mountOptions), volume.BtrfsSubvolumeMountOption(subvolume)), nil
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sys/unix"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/audit"
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+// Drain prepares a graceful shutdown. The exports are removed from the nfs server, so no client
+// mounts the volume or starts new operations anymore, and the outstanding operations get up to
+// half of the timeout to finish. Then the filesystem is synced, for the rest of the timeout, even
+// if draining the nfs server failed. Stopping the nfs server and unmounting is left to Shutdown.
+func (m *ShareManager) Drain(timeout time.Duration) (err error) {
+	if !m.ShareIsExported() {
+		return nil
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(m.context, timeout)
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "ShareManager.Drain", attribute.String("timeout", timeout.String()))
+	defer func() {
+		tracing.End(span, err)
+		m.auditLogger.LogResult(audit.Entry{
+			Event:  audit.EventDrain,
+			Volume: m.volume.Name,
+		}, start, err)
+	}()
+
+	m.logger.Infof("Draining share manager for up to %v", timeout)
+	m.SetShareExported(false)
+
+	drainCtx, cancelDrain := context.WithTimeout(ctx, timeout/2)
+	defer cancelDrain()
+	if err := m.nfsServer.Drain(drainCtx); err != nil {
+		err = errors.Wrap(err, "failed to drain nfs server")
+		return errors.CombineErrors(err, syncFilesystem(ctx, types.GetMountPath(m.volume.Name)))
+	}
+	m.logger.Info("Drained nfs server")
+
+	return syncFilesystem(ctx, types.GetMountPath(m.volume.Name))
+}
+
+// syncFilesystem flushes the filesystem to the device. A sync may block on a stuck device,
+// then it is left behind once ctx is done.
+func syncFilesystem(ctx context.Context, mountPath string) error {
+	f, err := os.Open(mountPath)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			_ = f.Close()
+		}()
+		done <- unix.Syncfs(int(f.Fd()))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return errors.Wrapf(err, "failed to sync filesystem %v", mountPath)
+		}
+		return nil
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "timed out syncing filesystem %v", mountPath)
+	}
+}
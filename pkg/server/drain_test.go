@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSyncFilesystem(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "mounted directory", path: dir},
+		{name: "missing directory", path: filepath.Join(dir, "missing"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := syncFilesystem(ctx, tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("syncFilesystem(%v) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
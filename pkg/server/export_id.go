@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+	"github.com/longhorn/longhorn-share-manager/pkg/util"
+)
+
+const (
+	exportIDsFile = "export-ids.json"
+
+	// firstPersistedExportID leaves 1 to the export of the volume
+	firstPersistedExportID = 2
+)
+
+// persistedExportIDs are the export ids of the sub-exports and snapshots, kept on the volume so
+// an export keeps its Export_Id and Filesystem_id when the config is rebuilt after a failover.
+// Next is only increased, a deleted export does not pass its id on while clients may still
+// hold file handles of it.
+type persistedExportIDs struct {
+	Next uint16            `json:"next"`
+	IDs  map[string]uint16 `json:"ids"`
+}
+
+func subExportIDKey(name string) string {
+	return "sub-export/" + name
+}
+
+func snapshotExportIDKey(name string) string {
+	return "snapshot/" + name
+}
+
+// claimExportID returns the persisted export id of the key, or persists the next free one
+func (m *ShareManager) claimExportID(key string) (uint16, error) {
+	m.exportIDMutex.Lock()
+	defer m.exportIDMutex.Unlock()
+
+	exportIDs, err := loadExportIDs(m.volume.Name)
+	if err != nil {
+		return 0, err
+	}
+	if id, ok := exportIDs.IDs[key]; ok {
+		return id, nil
+	}
+
+	used := map[uint16]bool{}
+	for _, id := range exportIDs.IDs {
+		used[id] = true
+	}
+	id := max(exportIDs.Next, firstPersistedExportID)
+	for ; used[id]; id++ {
+		if id == math.MaxUint16 {
+			return 0, errors.New("no export id left")
+		}
+	}
+
+	exportIDs.IDs[key] = id
+	exportIDs.Next = id
+	if id < math.MaxUint16 {
+		exportIDs.Next++
+	}
+	if err := saveExportIDs(m.volume.Name, exportIDs); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// releaseExportID forgets the export id of a deleted export
+func (m *ShareManager) releaseExportID(key string) error {
+	m.exportIDMutex.Lock()
+	defer m.exportIDMutex.Unlock()
+
+	exportIDs, err := loadExportIDs(m.volume.Name)
+	if err != nil {
+		return err
+	}
+	if _, ok := exportIDs.IDs[key]; !ok {
+		return nil
+	}
+
+	delete(exportIDs.IDs, key)
+	return saveExportIDs(m.volume.Name, exportIDs)
+}
+
+func loadExportIDs(volumeName string) (*persistedExportIDs, error) {
+	exportIDs := &persistedExportIDs{IDs: map[string]uint16{}}
+
+	path := filepath.Join(types.GetStatePath(volumeName), exportIDsFile)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return exportIDs, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read export ids from %v", path)
+	}
+
+	if err := json.Unmarshal(content, exportIDs); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse export ids in %v", path)
+	}
+	if exportIDs.IDs == nil {
+		exportIDs.IDs = map[string]uint16{}
+	}
+	return exportIDs, nil
+}
+
+func saveExportIDs(volumeName string, exportIDs *persistedExportIDs) error {
+	stateDir := types.GetStatePath(volumeName)
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create state directory %v", stateDir)
+	}
+
+	content, err := json.MarshalIndent(exportIDs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(stateDir, exportIDsFile)
+	if err := util.WriteFileAtomic(path, content, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write export ids to %v", path)
+	}
+	return nil
+}
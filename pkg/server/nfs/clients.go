@@ -0,0 +1,113 @@
+package nfs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	NFSPort = 2049
+
+	tcpStateEstablished = "01"
+)
+
+var procNetTCPFiles = []string{"/proc/net/tcp", "/proc/net/tcp6"}
+
+// Client is an NFS client with established connections to the server.
+type Client struct {
+	Address     string
+	Connections int
+}
+
+// ListClients returns the clients connected to the NFS port, found in the TCP connection
+// table of the network namespace. Clients which are idle after a failover and have not
+// reconnected yet are not known.
+func ListClients() ([]Client, error) {
+	connections := map[netip.Addr]int{}
+	for _, file := range procNetTCPFiles {
+		if err := readEstablishedConnections(file, NFSPort, connections); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+	}
+
+	clients := make([]Client, 0, len(connections))
+	for addr, count := range connections {
+		clients = append(clients, Client{Address: addr.String(), Connections: count})
+	}
+	slices.SortFunc(clients, func(a, b Client) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+	return clients, nil
+}
+
+// readEstablishedConnections counts the established connections per remote address to the local port.
+// The lines look like: "0: 0100007F:0801 0200007F:C350 01 ...", addresses and ports are in hex.
+func readEstablishedConnections(file string, localPort uint16, connections map[netip.Addr]int) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip the header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpStateEstablished {
+			continue
+		}
+
+		_, port, err := parseProcNetAddress(fields[1])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse local address in %v", file)
+		}
+		if port != localPort {
+			continue
+		}
+
+		remote, _, err := parseProcNetAddress(fields[2])
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse remote address in %v", file)
+		}
+		connections[remote]++
+	}
+	return scanner.Err()
+}
+
+// parseProcNetAddress parses an address of /proc/net/tcp{,6}. The address consists of
+// 32-bit words in host byte order, the port is big endian.
+func parseProcNetAddress(address string) (netip.Addr, uint16, error) {
+	hexIP, hexPort, ok := strings.Cut(address, ":")
+	if !ok {
+		return netip.Addr{}, 0, errors.Errorf("invalid address %q", address)
+	}
+
+	port, err := strconv.ParseUint(hexPort, 16, 16)
+	if err != nil {
+		return netip.Addr{}, 0, errors.Wrapf(err, "invalid port in address %q", address)
+	}
+
+	raw, err := hex.DecodeString(hexIP)
+	if err != nil || (len(raw) != 4 && len(raw) != 16) {
+		return netip.Addr{}, 0, errors.Errorf("invalid IP in address %q", address)
+	}
+	for i := 0; i < len(raw); i += 4 {
+		binary.BigEndian.PutUint32(raw[i:], binary.NativeEndian.Uint32(raw[i:]))
+	}
+
+	ip, _ := netip.AddrFromSlice(raw)
+	return ip.Unmap(), uint16(port), nil
+}
@@ -0,0 +1,84 @@
+package nfs
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// the addresses below are written by a little endian host
+func skipOnBigEndian(t *testing.T) {
+	if binary.NativeEndian.Uint16([]byte{1, 0}) != 1 {
+		t.Skip("addresses of /proc/net/tcp are in host byte order")
+	}
+}
+
+func TestParseProcNetAddress(t *testing.T) {
+	skipOnBigEndian(t)
+
+	tests := []struct {
+		address  string
+		wantIP   string
+		wantPort uint16
+		wantErr  bool
+	}{
+		{address: "0100007F:0801", wantIP: "127.0.0.1", wantPort: 2049},
+		{address: "0500200A:C350", wantIP: "10.32.0.5", wantPort: 50000},
+		{address: "00000000000000000000000001000000:0801", wantIP: "::1", wantPort: 2049},
+		{address: "0000000000000000FFFF00000500200A:0801", wantIP: "10.32.0.5", wantPort: 2049},
+		{address: "B80D0120000000000000000001000000:0016", wantIP: "2001:db8::1", wantPort: 22},
+		{address: "0100007F", wantErr: true},
+		{address: "0100007F:XYZ", wantErr: true},
+		{address: "0100007F:10000", wantErr: true},
+		{address: "01007F:0801", wantErr: true},
+		{address: "ZZ00007F:0801", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			ip, port, err := parseProcNetAddress(tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseProcNetAddress(%q) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if ip.String() != tt.wantIP || port != tt.wantPort {
+				t.Errorf("parseProcNetAddress(%q) = %v, %v, want %v, %v", tt.address, ip, port, tt.wantIP, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestReadEstablishedConnections(t *testing.T) {
+	skipOnBigEndian(t)
+
+	content := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0801 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 0A00200A:0801 0500200A:03FF 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 20 4 30 10 -1
+   2: 0A00200A:0801 0500200A:0400 01 00000000:00000000 00:00000000 00000000     0        0 1003 1 0000000000000000 20 4 30 10 -1
+   3: 0A00200A:0801 0600200A:0401 01 00000000:00000000 00:00000000 00000000     0        0 1004 1 0000000000000000 20 4 30 10 -1
+   4: 0A00200A:0801 0700200A:0402 06 00000000:00000000 00:00000000 00000000     0        0 0 3 0000000000000000
+   5: 0A00200A:2580 0800200A:C350 01 00000000:00000000 00:00000000 00000000     0        0 1005 1 0000000000000000 20 4 30 10 -1
+`
+	file := filepath.Join(t.TempDir(), "tcp")
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	connections := map[netip.Addr]int{}
+	if err := readEstablishedConnections(file, NFSPort, connections); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[netip.Addr]int{
+		netip.MustParseAddr("10.32.0.5"): 2,
+		netip.MustParseAddr("10.32.0.6"): 1,
+	}
+	if !reflect.DeepEqual(connections, want) {
+		t.Errorf("readEstablishedConnections() = %v, want %v", connections, want)
+	}
+}
@@ -0,0 +1,272 @@
+package nfs
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ConfigBlock is a block of the nfs server config, such as EXPORT or its FSAL sub block.
+// Block and parameter names are case insensitive.
+type ConfigBlock struct {
+	Name   string
+	Line   int
+	Params []ConfigParam
+	Blocks []*ConfigBlock
+}
+
+type ConfigParam struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// Param returns the first parameter with the given key
+func (b *ConfigBlock) Param(key string) (ConfigParam, bool) {
+	for _, param := range b.Params {
+		if strings.EqualFold(param.Key, key) {
+			return param, true
+		}
+	}
+	return ConfigParam{}, false
+}
+
+// Block returns the first sub block with the given name
+func (b *ConfigBlock) Block(name string) (*ConfigBlock, bool) {
+	for _, block := range b.Blocks {
+		if strings.EqualFold(block.Name, name) {
+			return block, true
+		}
+	}
+	return nil, false
+}
+
+// ConfigProblem is an issue found in the config which prevents ganesha from loading an export
+type ConfigProblem struct {
+	Line    int
+	Message string
+}
+
+type configToken struct {
+	text   string
+	line   int
+	quoted bool
+}
+
+// tokenizeConfig splits the config into words, quoted strings and the delimiters { } = ;
+// Comments start with # and run to the end of the line.
+func tokenizeConfig(content []byte) ([]configToken, error) {
+	tokens := []configToken{}
+	line := 1
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+		case c == '{' || c == '}' || c == '=' || c == ';':
+			tokens = append(tokens, configToken{text: string(c), line: line})
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(content) && content[end] != c && content[end] != '\n' {
+				end++
+			}
+			if end >= len(content) || content[end] != c {
+				return nil, errors.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, configToken{text: string(content[i+1 : end]), line: line, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(content) && !strings.ContainsRune(" \t\r\n,#{}=;\"'", rune(content[end])) {
+				end++
+			}
+			tokens = append(tokens, configToken{text: string(content[i:end]), line: line})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func isConfigDelimiter(token configToken) bool {
+	return !token.quoted && len(token.text) == 1 && strings.Contains("{}=;", token.text)
+}
+
+// ParseConfig parses a ganesha config into its top level blocks.
+// Include directives are skipped, the included files are not read.
+func ParseConfig(content []byte) ([]*ConfigBlock, error) {
+	tokens, err := tokenizeConfig(content)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &ConfigBlock{}
+	stack := []*ConfigBlock{root}
+	for i := 0; i < len(tokens); {
+		token := tokens[i]
+		current := stack[len(stack)-1]
+
+		switch {
+		case token.text == "}" && !token.quoted:
+			if len(stack) == 1 {
+				return nil, errors.Errorf("line %d: unexpected }", token.line)
+			}
+			stack = stack[:len(stack)-1]
+			i++
+		case token.text == ";" && !token.quoted:
+			// a block may be terminated by a ;
+			i++
+		case isConfigDelimiter(token):
+			return nil, errors.Errorf("line %d: unexpected %v", token.line, token.text)
+		case len(stack) == 1 && strings.HasPrefix(token.text, "%"):
+			// skip %include and similar directives up to the end of the line
+			for i < len(tokens) && tokens[i].line == token.line {
+				i++
+			}
+		case i+1 < len(tokens) && tokens[i+1].text == "{" && !tokens[i+1].quoted:
+			block := &ConfigBlock{Name: token.text, Line: token.line}
+			current.Blocks = append(current.Blocks, block)
+			stack = append(stack, block)
+			i += 2
+		case i+1 < len(tokens) && tokens[i+1].text == "=" && !tokens[i+1].quoted:
+			if len(stack) == 1 {
+				return nil, errors.Errorf("line %d: parameter %v outside of a block", token.line, token.text)
+			}
+			values := []string{}
+			end := i + 2
+			for ; end < len(tokens) && !isConfigDelimiter(tokens[end]); end++ {
+				values = append(values, tokens[end].text)
+			}
+			if end >= len(tokens) || tokens[end].text != ";" {
+				return nil, errors.Errorf("line %d: missing ; after parameter %v", token.line, token.text)
+			}
+			current.Params = append(current.Params, ConfigParam{
+				Key:   token.text,
+				Value: strings.Join(values, ", "),
+				Line:  token.line,
+			})
+			i = end + 1
+		default:
+			return nil, errors.Errorf("line %d: expected { or = after %v", token.line, token.text)
+		}
+	}
+
+	if len(stack) > 1 {
+		block := stack[len(stack)-1]
+		return nil, errors.Errorf("line %d: block %v is not closed", block.Line, block.Name)
+	}
+	return root.Blocks, nil
+}
+
+// ValidateConfig parses the config and checks the EXPORT blocks for values ganesha requires
+// to be unique and for a missing FSAL. The problems are sorted by line.
+func ValidateConfig(content []byte) ([]ConfigProblem, error) {
+	blocks, err := ParseConfig(content)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []ConfigProblem{}
+	seen := map[string]map[string]int{
+		"Export_Id":     {},
+		"Pseudo":        {},
+		"Filesystem_id": {},
+	}
+	checkUnique := func(block *ConfigBlock, key string, normalize func(string) string) {
+		param, ok := block.Param(key)
+		if !ok {
+			return
+		}
+		value := normalize(param.Value)
+		if first, ok := seen[key][value]; ok {
+			problems = append(problems, ConfigProblem{
+				Line:    param.Line,
+				Message: fmt.Sprintf("duplicate %v %v, already used on line %d", key, param.Value, first),
+			})
+			return
+		}
+		seen[key][value] = param.Line
+	}
+
+	for _, block := range blocks {
+		if !strings.EqualFold(block.Name, "EXPORT") {
+			continue
+		}
+
+		if _, ok := block.Param("Export_Id"); !ok {
+			problems = append(problems, ConfigProblem{Line: block.Line, Message: "export has no Export_Id"})
+		}
+		if fsal, ok := block.Block("FSAL"); !ok {
+			problems = append(problems, ConfigProblem{Line: block.Line, Message: "export has no FSAL"})
+		} else if _, ok := fsal.Param("Name"); !ok {
+			problems = append(problems, ConfigProblem{Line: fsal.Line, Message: "FSAL has no Name"})
+		}
+
+		checkUnique(block, "Export_Id", normalizeConfigNumber)
+		checkUnique(block, "Pseudo", filepath.Clean)
+		checkUnique(block, "Filesystem_id", func(value string) string { return value })
+	}
+
+	slices.SortStableFunc(problems, func(a, b ConfigProblem) int {
+		return a.Line - b.Line
+	})
+	return problems, nil
+}
+
+// normalizeConfigNumber makes 01 and 1 compare equal
+func normalizeConfigNumber(value string) string {
+	if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return strconv.FormatUint(n, 10)
+	}
+	return value
+}
+
+// RenderConfig returns the config the nfs server would run with after exporting the volumes
+// in the given order with the export options.
+func RenderConfig(exportPath string, volumes []string, exportOptions ExportOptions, options ConfigOptions) ([]byte, error) {
+	exportOptions, err := exportOptions.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	config := getUpdatedGaneshConfig(defaultConfig, options)
+	exports := &ExportMap{idToVolume: map[uint16]string{}, volumeToid: map[string]uint16{}}
+	for i, volume := range volumes {
+		if volume == "" {
+			return nil, errors.New("volume name is empty")
+		}
+		if slices.Contains(volumes[:i], volume) {
+			return nil, errors.Errorf("volume %v is exported more than once", volume)
+		}
+		config = append(config, generateExportBlock(exportPath, volume, exports.claim(volume), options.Protocols(), exportOptions)...)
+	}
+	return config, nil
+}
+
+// configProtocols returns the NFS versions the config serves, so exports match the server
+func configProtocols(config []byte) string {
+	blocks, err := ParseConfig(config)
+	if err != nil {
+		return defaultProtocols
+	}
+	for _, block := range blocks {
+		if !strings.EqualFold(block.Name, "NFS_Core_Param") {
+			continue
+		}
+		if protocols, ok := block.Param("Protocols"); ok {
+			return protocols.Value
+		}
+	}
+	return defaultProtocols
+}
@@ -0,0 +1,219 @@
+package nfs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []configToken
+		wantErr bool
+	}{
+		{name: "empty", content: "", want: []configToken{}},
+		{
+			name:    "block with parameter",
+			content: "EXPORT {\n\tPath = /export/vol; # the volume\n}\n",
+			want: []configToken{
+				{text: "EXPORT", line: 1}, {text: "{", line: 1},
+				{text: "Path", line: 2}, {text: "=", line: 2}, {text: "/export/vol", line: 2}, {text: ";", line: 2},
+				{text: "}", line: 3},
+			},
+		},
+		{
+			name:    "list and quoted strings",
+			content: "Protocols = 3, 4;\nPath = \"/export/a b\";Tag='x;y';",
+			want: []configToken{
+				{text: "Protocols", line: 1}, {text: "=", line: 1}, {text: "3", line: 1}, {text: "4", line: 1}, {text: ";", line: 1},
+				{text: "Path", line: 2}, {text: "=", line: 2}, {text: "/export/a b", line: 2, quoted: true}, {text: ";", line: 2},
+				{text: "Tag", line: 2}, {text: "=", line: 2}, {text: "x;y", line: 2, quoted: true}, {text: ";", line: 2},
+			},
+		},
+		{name: "unterminated string", content: "Path = \"/export;\n", wantErr: true},
+		{name: "mismatched quotes", content: "Path = \"/export';", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenizeConfig([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tokenizeConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenizeConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []*ConfigBlock
+		wantErr bool
+	}{
+		{
+			name:    "nested blocks",
+			content: "%include \"/etc/ganesha/common.conf\"\nEXPORT\n{\n\tExport_Id = 1;\n\tFSAL {\n\t\tName = VFS;\n\t};\n}\nNFS_Core_Param { Protocols = 3, 4; }\n",
+			want: []*ConfigBlock{
+				{
+					Name:   "EXPORT",
+					Line:   2,
+					Params: []ConfigParam{{Key: "Export_Id", Value: "1", Line: 4}},
+					Blocks: []*ConfigBlock{{Name: "FSAL", Line: 5, Params: []ConfigParam{{Key: "Name", Value: "VFS", Line: 6}}}},
+				},
+				{Name: "NFS_Core_Param", Line: 9, Params: []ConfigParam{{Key: "Protocols", Value: "3, 4", Line: 9}}},
+			},
+		},
+		{name: "unexpected closing brace", content: "EXPORT { }\n}\n", wantErr: true},
+		{name: "unclosed block", content: "EXPORT {\n\tExport_Id = 1;\n", wantErr: true},
+		{name: "parameter outside of a block", content: "Export_Id = 1;\n", wantErr: true},
+		{name: "missing semicolon", content: "EXPORT {\n\tExport_Id = 1\n}\n", wantErr: true},
+		{name: "word without block or value", content: "EXPORT {\n\tExport_Id;\n}\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConfig([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	export := func(id, pseudo, fsid string) string {
+		return "EXPORT {\n" +
+			"\tExport_Id = " + id + ";\n" +
+			"\tPseudo = " + pseudo + ";\n" +
+			"\tFilesystem_id = " + fsid + ";\n" +
+			"\tFSAL { Name = VFS; }\n" +
+			"}\n"
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []ConfigProblem
+	}{
+		{name: "valid", content: export("1", "/vol", "1.0") + export("2", "/vol/sub", "2.0"), want: []ConfigProblem{}},
+		{
+			name:    "duplicates",
+			content: export("1", "/vol", "1.0") + export("01", "/vol/", "1.0"),
+			want: []ConfigProblem{
+				{Line: 8, Message: "duplicate Export_Id 01, already used on line 2"},
+				{Line: 9, Message: "duplicate Pseudo /vol/, already used on line 3"},
+				{Line: 10, Message: "duplicate Filesystem_id 1.0, already used on line 4"},
+			},
+		},
+		{
+			name:    "missing id and FSAL",
+			content: "EXPORT {\n\tPseudo = /vol;\n}\nEXPORT {\n\tExport_Id = 2;\n\tFSAL { }\n}\n",
+			want: []ConfigProblem{
+				{Line: 1, Message: "export has no Export_Id"},
+				{Line: 1, Message: "export has no FSAL"},
+				{Line: 6, Message: "FSAL has no Name"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateConfig([]byte(tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		volumes        []string
+		exportOptions  ExportOptions
+		options        ConfigOptions
+		wantAccessType string
+		wantClients    string
+		wantProtocols  string
+		wantErr        bool
+	}{
+		{name: "defaults", volumes: []string{"pvc-1"}, wantAccessType: "RW", wantProtocols: "4"},
+		{name: "several volumes with NFSv3", volumes: []string{"pvc-1", "pvc-2"}, options: ConfigOptions{EnableNFSv3: true},
+			wantAccessType: "RW", wantProtocols: "3, 4"},
+		{name: "read-only", volumes: []string{"pvc-1"}, exportOptions: ExportOptions{AccessType: "RO", Squash: "All"},
+			wantAccessType: "RO", wantProtocols: "4"},
+		{name: "clients", volumes: []string{"pvc-1"}, exportOptions: ExportOptions{Clients: []string{"10.0.0.0/8", "node-1"}},
+			wantAccessType: "None", wantClients: "10.0.0.0/8, node-1", wantProtocols: "4"},
+		{name: "invalid access type", volumes: []string{"pvc-1"}, exportOptions: ExportOptions{AccessType: "rw"}, wantErr: true},
+		{name: "invalid client", volumes: []string{"pvc-1"}, exportOptions: ExportOptions{Clients: []string{"a;b"}}, wantErr: true},
+		{name: "empty volume", volumes: []string{""}, wantErr: true},
+		{name: "duplicate volume", volumes: []string{"pvc-1", "pvc-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := RenderConfig("/export", tt.volumes, tt.exportOptions, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			problems, err := ValidateConfig(config)
+			if err != nil {
+				t.Fatalf("failed to parse rendered config: %v\n%s", err, config)
+			}
+			if len(problems) > 0 {
+				t.Errorf("rendered config has problems %+v\n%s", problems, config)
+			}
+
+			blocks, _ := ParseConfig(config)
+			exports := []*ConfigBlock{}
+			for _, block := range blocks {
+				if strings.EqualFold(block.Name, "EXPORT") {
+					exports = append(exports, block)
+				}
+			}
+			if len(exports) != len(tt.volumes) {
+				t.Fatalf("rendered %d exports, want %d", len(exports), len(tt.volumes))
+			}
+			for i, export := range exports {
+				if path, _ := export.Param("Path"); path.Value != "/export/"+tt.volumes[i] {
+					t.Errorf("export %d has path %v, want /export/%v", i, path.Value, tt.volumes[i])
+				}
+				if accessType, _ := export.Param("Access_Type"); accessType.Value != tt.wantAccessType {
+					t.Errorf("export %d has access type %v, want %v", i, accessType.Value, tt.wantAccessType)
+				}
+				if protocols, _ := export.Param("Protocols"); protocols.Value != tt.wantProtocols {
+					t.Errorf("export %d has protocols %v, want %v", i, protocols.Value, tt.wantProtocols)
+				}
+				client, ok := export.Block("CLIENT")
+				if tt.wantClients == "" {
+					if ok {
+						t.Errorf("export %d has an unexpected CLIENT block", i)
+					}
+					continue
+				}
+				if !ok {
+					t.Fatalf("export %d has no CLIENT block", i)
+				}
+				if clients, _ := client.Param("Clients"); clients.Value != tt.wantClients {
+					t.Errorf("export %d has clients %v, want %v", i, clients.Value, tt.wantClients)
+				}
+			}
+		})
+	}
+}
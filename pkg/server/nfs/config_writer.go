@@ -0,0 +1,84 @@
+package nfs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/util"
+)
+
+const defaultConfigMode = 0600
+
+// configMutexes serializes the writers of a config file within the process, the server writes
+// the initial config and its exporter updates it
+var configMutexes sync.Map
+
+// lockConfig takes the process wide mutex of the config file and a flock on a lock file next
+// to it, which also keeps other processes from losing updates. The config itself cannot be
+// locked, because it is replaced on every write.
+func lockConfig(configPath string) (func(), error) {
+	value, _ := configMutexes.LoadOrStore(filepath.Clean(configPath), &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+
+	lockFile, err := os.OpenFile(configPath+".lock", os.O_CREATE|os.O_RDWR, defaultConfigMode)
+	if err != nil {
+		mutex.Unlock()
+		return nil, errors.Wrapf(err, "failed to open lock file of config %v", configPath)
+	}
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+		_ = lockFile.Close()
+		mutex.Unlock()
+		return nil, errors.Wrapf(err, "failed to lock config %v", configPath)
+	}
+
+	return func() {
+		// closing the file releases the flock
+		if err := lockFile.Close(); err != nil {
+			logrus.WithError(err).Errorf("Failed to close lock file of config %v", configPath)
+		}
+		mutex.Unlock()
+	}, nil
+}
+
+// writeConfig replaces the config file with the given content
+func writeConfig(configPath string, config []byte) error {
+	unlock, err := lockConfig(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return util.WriteFileAtomic(configPath, config, defaultConfigMode)
+}
+
+// updateConfig replaces the config file with the result of update, which gets the current
+// content. The file is left untouched if update fails or returns the content unchanged.
+func updateConfig(configPath string, update func(config []byte) ([]byte, error)) error {
+	unlock, err := lockConfig(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	config, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	newConfig, err := update(config)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(config, newConfig) {
+		return nil
+	}
+
+	return util.WriteFileAtomic(configPath, newConfig, defaultConfigMode)
+}
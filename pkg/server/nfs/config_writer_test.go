@@ -0,0 +1,186 @@
+package nfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+func TestWriteConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing os.FileMode
+		wantMode os.FileMode
+	}{
+		{name: "new config", wantMode: defaultConfigMode},
+		{name: "existing config keeps its mode", existing: 0644, wantMode: 0644},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "vfs.conf")
+			if tt.existing != 0 {
+				if err := os.WriteFile(configPath, []byte("old"), tt.existing); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chmod(configPath, tt.existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := writeConfig(configPath, []byte("new")); err != nil {
+				t.Fatalf("writeConfig() error = %v", err)
+			}
+
+			assertConfig(t, configPath, "new")
+			info, err := os.Stat(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != tt.wantMode {
+				t.Errorf("writeConfig() mode = %v, want %v", info.Mode().Perm(), tt.wantMode)
+			}
+			assertNoTempFiles(t, dir)
+		})
+	}
+}
+
+func TestUpdateConfig(t *testing.T) {
+	errUpdate := errors.New("update failed")
+
+	tests := []struct {
+		name        string
+		update      func(config []byte) ([]byte, error)
+		wantErr     error
+		want        string
+		wantReplace bool
+	}{
+		{
+			name:        "changed",
+			update:      func(config []byte) ([]byte, error) { return append(config, " new"...), nil },
+			want:        "old new",
+			wantReplace: true,
+		},
+		{
+			name:   "unchanged",
+			update: func(config []byte) ([]byte, error) { return config, nil },
+			want:   "old",
+		},
+		{
+			name:    "update fails",
+			update:  func(config []byte) ([]byte, error) { return []byte("partial"), errUpdate },
+			wantErr: errUpdate,
+			want:    "old",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "vfs.conf")
+			if err := os.WriteFile(configPath, []byte("old"), defaultConfigMode); err != nil {
+				t.Fatal(err)
+			}
+			before, err := os.Stat(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := updateConfig(configPath, tt.update); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("updateConfig() error = %v, want %v", err, tt.wantErr)
+			}
+
+			assertConfig(t, configPath, tt.want)
+			after, err := os.Stat(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the config is replaced by a rename, so a rewritten config is a different file
+			if replaced := !os.SameFile(before, after); replaced != tt.wantReplace {
+				t.Errorf("updateConfig() replaced the config = %v, want %v", replaced, tt.wantReplace)
+			}
+			assertNoTempFiles(t, dir)
+		})
+	}
+}
+
+func TestUpdateConfigConcurrent(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "vfs.conf")
+	if err := writeConfig(configPath, []byte("0")); err != nil {
+		t.Fatal(err)
+	}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := updateConfig(configPath, func(config []byte) ([]byte, error) {
+				count, err := strconv.Atoi(string(config))
+				if err != nil {
+					return nil, err
+				}
+				return []byte(strconv.Itoa(count + 1)), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assertConfig(t, configPath, fmt.Sprint(writers))
+}
+
+func TestLockConfigFlock(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "vfs.conf")
+
+	unlock, err := lockConfig(configPath)
+	if err != nil {
+		t.Fatalf("lockConfig() error = %v", err)
+	}
+
+	// another process opens the lock file on its own and must not get the lock
+	lockFile, err := os.Open(configPath + ".lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lockFile.Close()
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); !errors.Is(err, syscall.EWOULDBLOCK) {
+		t.Fatalf("flock while the config is locked error = %v, want %v", err, syscall.EWOULDBLOCK)
+	}
+
+	unlock()
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		t.Fatalf("flock after the config was unlocked error = %v", err)
+	}
+}
+
+func assertConfig(t *testing.T, configPath, want string) {
+	t.Helper()
+	config, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(config) != want {
+		t.Errorf("config = %q, want %q", config, want)
+	}
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, ".vfs.conf.tmp-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("temporary files %v were left behind", matches)
+	}
+}
@@ -0,0 +1,121 @@
+package nfs
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	dbusSocket = "/run/dbus/system_bus_socket"
+
+	ganeshaBusName       = "org.ganesha.nfsd"
+	exportMgrPath        = "/org/ganesha/nfsd/ExportMgr"
+	exportMgrInterface   = "org.ganesha.nfsd.exportmgr"
+	exportStatsInterface = "org.ganesha.nfsd.exportstats"
+)
+
+// ErrDBusUnavailable is returned when the nfs server cannot be managed through DBus, because the
+// system bus is not running or ganesha did not register on it
+var ErrDBusUnavailable = errors.New("DBus interface of the nfs server is not available")
+
+// callGanesha calls a method of the export manager of ganesha and returns the printed reply
+func callGanesha(ctx context.Context, method string, args ...string) (string, error) {
+	if _, err := os.Stat(dbusSocket); err != nil {
+		return "", errors.Wrapf(ErrDBusUnavailable, "system bus socket: %v", err)
+	}
+
+	cmdArgs := append([]string{"--system", "--print-reply", "--dest=" + ganeshaBusName, exportMgrPath, method}, args...)
+	out, err := exec.CommandContext(ctx, "dbus-send", cmdArgs...).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(out), "org.freedesktop.DBus.Error.ServiceUnknown") {
+			return "", errors.Wrapf(ErrDBusUnavailable, "%s", strings.TrimSpace(string(out)))
+		}
+		return "", errors.Wrapf(err, "failed to call %v of the nfs server: %s", method, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// removeExport removes the export from the running nfs server, the config is left untouched
+func removeExport(ctx context.Context, id uint16) error {
+	_, err := callGanesha(ctx, exportMgrInterface+".RemoveExport", "uint16:"+strconv.Itoa(int(id)))
+	return err
+}
+
+// globalOps returns the number of operations the nfs server has completed since it started
+func globalOps(ctx context.Context) (uint64, error) {
+	reply, err := callGanesha(ctx, exportStatsInterface+".GetGlobalOPS")
+	if err != nil {
+		return 0, err
+	}
+	return parseGlobalOps(reply)
+}
+
+// parseGlobalOps sums the operation counts of the protocols in a GetGlobalOPS reply printed by
+// dbus-send. The reply starts with the status and its message, followed by a timestamp and the
+// counts, each of which comes after the name of its protocol.
+func parseGlobalOps(reply string) (uint64, error) {
+	var (
+		total   uint64
+		status  string
+		message string
+		prev    string
+	)
+	scanner := bufio.NewScanner(strings.NewReader(reply))
+	for scanner.Scan() {
+		kind, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		switch {
+		case kind == "boolean" && status == "":
+			status = value
+		case kind == "string" && prev == "boolean":
+			message = strings.Trim(value, `"`)
+		case kind == "uint64" && prev == "string":
+			count, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return 0, errors.Wrapf(err, "invalid operation count %q", value)
+			}
+			total += count
+		}
+		prev = kind
+	}
+
+	if status != "true" {
+		return 0, errors.Errorf("nfs server did not return its operation counts: %v", message)
+	}
+	return total, nil
+}
+
+// waitForIdle waits until no operation completed for an interval or ctx is done. Ganesha has no
+// count of the operations in flight, so they are taken as done once the completed operations
+// stop advancing.
+func waitForIdle(ctx context.Context, interval time.Duration, count func(context.Context) (uint64, error)) error {
+	last, err := count(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "timed out waiting for the outstanding nfs operations")
+		case <-ticker.C:
+		}
+
+		current, err := count(ctx)
+		if err != nil {
+			return err
+		}
+		if current == last {
+			return nil
+		}
+		last = current
+	}
+}
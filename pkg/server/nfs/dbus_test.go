@@ -0,0 +1,127 @@
+package nfs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+const globalOpsReply = `method return time=1760860000.123456 sender=:1.1 -> destination=:1.7 serial=12 reply_serial=2
+   boolean true
+   string "OK"
+   struct {
+      uint64 1760860000
+      uint64 123456789
+   }
+   struct {
+      string "NFSv3:"
+      uint64 12
+      string "NFSv4:"
+      uint64 30
+      string "NLM4:"
+      uint64 0
+   }
+`
+
+func TestParseGlobalOps(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    uint64
+		wantErr bool
+	}{
+		{name: "counts of all protocols", reply: globalOpsReply, want: 42},
+		{
+			name: "empty status message",
+			reply: `method return time=1760860000.123456 sender=:1.1 -> destination=:1.7 serial=12 reply_serial=2
+   boolean true
+   string ""
+   struct {
+      uint64 1760860000
+      uint64 123456789
+   }
+   struct {
+      string "NFSv4:"
+      uint64 7
+   }
+`,
+			want: 7,
+		},
+		{
+			name: "stats disabled",
+			reply: `method return time=1760860000.123456 sender=:1.1 -> destination=:1.7 serial=12 reply_serial=2
+   boolean false
+   string "NFS stat counting disabled"
+`,
+			wantErr: true,
+		},
+		{name: "no status", reply: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGlobalOps(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGlobalOps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseGlobalOps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForIdle(t *testing.T) {
+	errCount := errors.New("count failed")
+
+	tests := []struct {
+		name      string
+		counts    []uint64
+		err       error
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "idle", counts: []uint64{5, 5}, wantCalls: 2},
+		{name: "outstanding operations finish", counts: []uint64{5, 8, 9, 9}, wantCalls: 4},
+		{name: "operations never stop", counts: []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, wantErr: context.DeadlineExceeded},
+		{name: "count fails", err: errCount, wantErr: errCount, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			calls := 0
+			err := waitForIdle(ctx, 10*time.Millisecond, func(ctx context.Context) (uint64, error) {
+				calls++
+				if tt.err != nil {
+					return 0, tt.err
+				}
+				return tt.counts[min(calls, len(tt.counts))-1], nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("waitForIdle() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantCalls != 0 && calls != tt.wantCalls {
+				t.Errorf("waitForIdle() counted %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDrainWithoutDBus(t *testing.T) {
+	if _, err := os.Stat(dbusSocket); err == nil {
+		t.Skipf("system bus %v is running", dbusSocket)
+	}
+
+	s := &Server{exporter: &Exporter{ExportMap: &ExportMap{
+		idToVolume: map[uint16]string{1: "vol"},
+		volumeToid: map[string]uint16{"vol": 1},
+	}}}
+	if err := s.Drain(context.Background()); !errors.Is(err, ErrDBusUnavailable) {
+		t.Errorf("Drain() error = %v, want %v", err, ErrDBusUnavailable)
+	}
+}
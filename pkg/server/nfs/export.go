@@ -1,21 +1,93 @@
 package nfs
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 
 	"github.com/cockroachdb/errors"
-	"github.com/sirupsen/logrus"
 
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
 	"github.com/longhorn/longhorn-share-manager/pkg/util"
 )
 
+// ExportOptions control the access to an export
+type ExportOptions struct {
+	// AccessType is RW, RO or None
+	AccessType string `json:"accessType"`
+	// Squash is None, Root, RootId or All
+	Squash string `json:"squash"`
+	// Clients restricts the access to these hosts or networks, all clients have access if empty
+	Clients []string `json:"clients,omitempty"`
+}
+
+// InvalidConfigChars would break the export block written to the nfs server config
+const InvalidConfigChars = " \t\r\n;{}#=\"'"
+
+var (
+	// DefaultExportOptions give all clients read-write access to the export of a volume
+	DefaultExportOptions = ExportOptions{AccessType: "RW", Squash: "None"}
+
+	accessTypes = []string{"RW", "RO", "None"}
+	squashes    = []string{"None", "Root", "RootId", "All"}
+)
+
+// Normalize applies the defaults to empty options and validates them
+func (o ExportOptions) Normalize() (ExportOptions, error) {
+	if o.AccessType == "" {
+		o.AccessType = DefaultExportOptions.AccessType
+	}
+	if !slices.Contains(accessTypes, o.AccessType) {
+		return o, errors.Errorf("access type %q must be one of %v", o.AccessType, accessTypes)
+	}
+	if o.Squash == "" {
+		o.Squash = DefaultExportOptions.Squash
+	}
+	if !slices.Contains(squashes, o.Squash) {
+		return o, errors.Errorf("squash %q must be one of %v", o.Squash, squashes)
+	}
+	for _, client := range o.Clients {
+		if client == "" || strings.ContainsAny(client, InvalidConfigChars+",") {
+			return o, errors.Errorf("client %q must be a host, network or netgroup", client)
+		}
+	}
+	return o, nil
+}
+
+// SubExport exports a directory of a volume with its own pseudo path and access options
+type SubExport struct {
+	Name string `json:"name"`
+	// Path is relative to the export of the volume
+	Path   string `json:"path"`
+	Pseudo string `json:"pseudo"`
+	ExportOptions
+}
+
+// SnapshotPseudoPath returns the pseudo path a snapshot of the volume is exported at
+func SnapshotPseudoPath(volume, name string) string {
+	return filepath.Join("/", volume, types.SnapshotsDirName, name)
+}
+
+// snapshotExportKey marks the snapshot exports in the config, sub-export names cannot start with
+// a dot, so the keys do not collide and DeleteSubExports removes the snapshot exports as well
+func snapshotExportKey(volume, name string) string {
+	return subExportKey(volume, filepath.Join(types.SnapshotsDirName, name))
+}
+
+// subExportKey marks the sub-exports in the config, volume names cannot contain a slash
+func subExportKey(volume, name string) string {
+	return volume + "/" + name
+}
+
 type ExportMap struct {
 	idToVolume map[uint16]string
 	volumeToid map[string]uint16
@@ -27,10 +99,11 @@ type Exporter struct {
 	configPath string
 	exportPath string
 
-	mapMutex  sync.RWMutex
-	fileMutex sync.Mutex
+	mapMutex sync.RWMutex
 }
 
+const defaultProtocols = "4"
+
 var exportRegex = regexp.MustCompile("Export_Id = ([0-9]+);#Volume=(.+)")
 
 func NewExporter(configPath, exportPath string) (*Exporter, error) {
@@ -57,8 +130,7 @@ func NewExporter(configPath, exportPath string) (*Exporter, error) {
 		configPath: configPath,
 		exportPath: exportPath,
 
-		mapMutex:  sync.RWMutex{},
-		fileMutex: sync.Mutex{},
+		mapMutex: sync.RWMutex{},
 	}, nil
 }
 
@@ -95,15 +167,20 @@ func (e *Exporter) claimID(volume string) uint16 {
 	e.mapMutex.Lock()
 	defer e.mapMutex.Unlock()
 
-	if id, ok := e.volumeToid[volume]; ok {
+	return e.claim(volume)
+}
+
+// claim assigns the lowest free id, so the volume of a share manager always gets 1
+func (m *ExportMap) claim(volume string) uint16 {
+	if id, ok := m.volumeToid[volume]; ok {
 		return id
 	}
 
 	id := uint16(1)
 	for ; ; id++ {
-		if _, ok := e.idToVolume[id]; !ok {
-			e.idToVolume[id] = volume
-			e.volumeToid[volume] = id
+		if _, ok := m.idToVolume[id]; !ok {
+			m.idToVolume[id] = volume
+			m.volumeToid[volume] = id
 			break
 		}
 	}
@@ -111,6 +188,19 @@ func (e *Exporter) claimID(volume string) uint16 {
 	return id
 }
 
+// claimFixedID assigns the given id, which must not be used by another export
+func (e *Exporter) claimFixedID(key string, id uint16) error {
+	e.mapMutex.Lock()
+	defer e.mapMutex.Unlock()
+
+	if other, ok := e.idToVolume[id]; ok && other != key {
+		return errors.Errorf("export id %v is already used by %v", id, other)
+	}
+	e.idToVolume[id] = key
+	e.volumeToid[key] = id
+	return nil
+}
+
 func (e *Exporter) deleteID(id uint16) {
 	e.mapMutex.Lock()
 	defer e.mapMutex.Unlock()
@@ -122,39 +212,133 @@ func (e *Exporter) deleteID(id uint16) {
 	delete(e.idToVolume, id)
 }
 
+// CreateExport adds the export block of the volume to the config. The export ids are reloaded
+// from the config first, since other exporters of the same file may have changed it.
 func (e *Exporter) CreateExport(volume string) (uint16, error) {
-	if id := e.GetExport(volume); id != 0 {
-		return id, nil
+	id, err := e.createExport(volume, 0, func(id uint16, protocols string) string {
+		return generateExportBlock(e.exportPath, volume, id, protocols, DefaultExportOptions)
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "error adding export of volume %s to config %s", volume, e.configPath)
 	}
+	return id, nil
+}
 
-	exportID := e.claimID(volume)
-	block := generateExportBlock(e.exportPath, volume, exportID)
+func (e *Exporter) DeleteExport(volume string) error {
+	return e.deleteExports(func(key string) bool {
+		return key == volume
+	})
+}
 
-	if err := e.addToConfig(block); err != nil {
-		e.deleteID(exportID)
-		return 0, errors.Wrapf(err, "error adding export block %s to config %s", block, e.configPath)
+// CreateSnapshotExport adds the export block of a snapshot mounted below the volume to the config
+// with the given export id
+func (e *Exporter) CreateSnapshotExport(volume, name string, exportID uint16, options ExportOptions) (uint16, error) {
+	key := snapshotExportKey(volume, name)
+	id, err := e.createExport(key, exportID, func(id uint16, protocols string) string {
+		return generateBlock(key, id, filepath.Join(e.exportPath, volume, types.SnapshotsDirName, name),
+			SnapshotPseudoPath(volume, name), protocols, options)
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "error adding export of snapshot %s of volume %s to config %s", name, volume, e.configPath)
 	}
-	return exportID, nil
+	return id, nil
 }
 
-func (e *Exporter) DeleteExport(volume string) error {
-	id, ok := e.volumeToid[volume]
-	if !ok {
-		return nil
+// GetSnapshotExport returns the export id of a snapshot, where 0 equals unexported
+func (e *Exporter) GetSnapshotExport(volume, name string) uint16 {
+	return e.GetExport(snapshotExportKey(volume, name))
+}
+
+func (e *Exporter) DeleteSnapshotExport(volume, name string) error {
+	return e.deleteExports(func(key string) bool {
+		return key == snapshotExportKey(volume, name)
+	})
+}
+
+// CreateSubExport adds the export block of a directory of the volume to the config with the given export id
+func (e *Exporter) CreateSubExport(volume string, subExport SubExport, exportID uint16) (uint16, error) {
+	id, err := e.createExport(subExportKey(volume, subExport.Name), exportID, func(id uint16, protocols string) string {
+		return generateSubExportBlock(e.exportPath, volume, subExport, id, protocols)
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "error adding sub-export %s of volume %s to config %s", subExport.Name, volume, e.configPath)
 	}
+	return id, nil
+}
 
-	// TODO: write a lexer and parser for the export config
-	// 	instead of doing these string manipulations
-	block := generateExportBlock(e.exportPath, volume, id)
-	if err := e.removeFromConfig(block); err != nil {
-		return err
+// GetSubExport returns the export id of a sub-export, where 0 equals unexported
+func (e *Exporter) GetSubExport(volume, name string) uint16 {
+	return e.GetExport(subExportKey(volume, name))
+}
+
+func (e *Exporter) DeleteSubExport(volume, name string) error {
+	return e.deleteExports(func(key string) bool {
+		return key == subExportKey(volume, name)
+	})
+}
+
+// DeleteSubExports removes all sub-exports of the volume from the config
+func (e *Exporter) DeleteSubExports(volume string) error {
+	return e.deleteExports(func(key string) bool {
+		return strings.HasPrefix(key, subExportKey(volume, ""))
+	})
+}
+
+// createExport claims an id for the key and appends the block generated for it, unless the
+// key is already exported. Without a fixed id the lowest free one is claimed.
+func (e *Exporter) createExport(key string, fixedID uint16, generateBlock func(id uint16, protocols string) string) (uint16, error) {
+	var exportID uint16
+	claimed := false
+	err := updateConfig(e.configPath, func(config []byte) ([]byte, error) {
+		e.loadIDs(config)
+		if exportID = e.GetExport(key); exportID != 0 {
+			return config, nil
+		}
+
+		if fixedID == 0 {
+			exportID = e.claimID(key)
+		} else {
+			if err := e.claimFixedID(key, fixedID); err != nil {
+				return nil, err
+			}
+			exportID = fixedID
+		}
+		claimed = true
+		return append(config, generateBlock(exportID, configProtocols(config))...), nil
+	})
+	if err != nil {
+		if claimed {
+			e.deleteID(exportID)
+		}
+		return 0, err
 	}
+	return exportID, nil
+}
 
-	e.deleteID(id)
-	return nil
+// deleteExports removes the blocks of the exports whose key matches
+func (e *Exporter) deleteExports(match func(key string) bool) error {
+	// TODO: write a lexer and parser for the export config
+	// 	instead of doing these string manipulations
+	return updateConfig(e.configPath, func(config []byte) ([]byte, error) {
+		e.loadIDs(config)
+		for key, id := range e.GetExportMap().volumeToid {
+			if !match(key) {
+				continue
+			}
+			config = removeExportBlock(config, key, id)
+			e.deleteID(id)
+		}
+		return config, nil
+	})
 }
 
-func (e *Exporter) ReloadExport() error {
+// ReloadExport makes ganesha reread the export configuration with a SIGHUP
+func (e *Exporter) ReloadExport(ctx context.Context) (err error) {
+	_, span := tracing.Start(ctx, "nfs.ReloadExport")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	processName := "ganesha.nfsd"
 
 	process, err := util.FindProcessByName(processName)
@@ -170,36 +354,77 @@ func (e *Exporter) ReloadExport() error {
 	return nil
 }
 
-func generateExportBlock(exportBase, volume string, id uint16) string {
-	squash := "None"
+func generateExportBlock(exportBase, volume string, id uint16, protocols string, options ExportOptions) string {
+	return generateBlock(volume, id, filepath.Join(exportBase, volume), filepath.Join("/", volume), protocols, options)
+}
+
+func generateSubExportBlock(exportBase, volume string, subExport SubExport, id uint16, protocols string) string {
+	return generateBlock(subExportKey(volume, subExport.Name), id, filepath.Join(exportBase, volume, subExport.Path),
+		subExport.Pseudo, protocols, subExport.ExportOptions)
+}
+
+// generateBlock returns an export block marked with the key, the block ends with the first
+// unindented closing brace after the marker
+func generateBlock(key string, id uint16, exportPath, pseudoPath, protocols string, options ExportOptions) string {
 	secType := "sys"
-	pseudoPath := filepath.Join("/", volume)
-	exportPath := filepath.Join(exportBase, volume)
 	exportID := strconv.FormatUint(uint64(id), 10)
-	volumeMarker := "#Volume=" + volume
 
-	return "\nEXPORT\n{\n" +
-		"\tExport_Id = " + exportID + ";" + volumeMarker + "\n" +
+	// with a client list, only the listed clients get access
+	accessType := options.AccessType
+	clients := ""
+	if len(options.Clients) > 0 {
+		clients = "\tCLIENT {\n" +
+			"\t\tClients = " + strings.Join(options.Clients, ", ") + ";\n" +
+			"\t\tAccess_Type = " + accessType + ";\n" +
+			"\t}\n"
+		accessType = "None"
+	}
+
+	return exportBlockStart(key, id) +
 		"\tPath = " + exportPath + ";\n" +
 		"\tPseudo = " + pseudoPath + ";\n" +
-		"\tProtocols = 4;\n" +
+		"\tProtocols = " + protocols + ";\n" +
 		"\tTransports = TCP;\n" +
-		"\tAccess_Type = RW;\n" +
-		"\tSquash = " + squash + ";\n" +
+		"\tAccess_Type = " + accessType + ";\n" +
+		"\tSquash = " + options.Squash + ";\n" +
 		"\tSecType = " + secType + ";\n" +
 		"\tFilesystem_id = " + exportID + "." + "0" + ";\n" +
-		"\tFSAL {\n\t\tName = VFS;\n\t}\n}\n"
+		"\tFSAL {\n\t\tName = VFS;\n\t}\n" +
+		clients +
+		"}\n"
+}
+
+func exportBlockStart(key string, id uint16) string {
+	return "\nEXPORT\n{\n" +
+		"\tExport_Id = " + strconv.FormatUint(uint64(id), 10) + ";" + "#Volume=" + key + "\n"
 }
 
-// getIDsFromConfig populates a map with existing ids found in the given config
-// file using the given regexp. Regexp must have a "digits" submatch.
+// removeExportBlock cuts the block generated for the key out of the config
+func removeExportBlock(config []byte, key string, id uint16) []byte {
+	start := bytes.Index(config, []byte(exportBlockStart(key, id)))
+	if start < 0 {
+		return config
+	}
+	end := bytes.Index(config[start:], []byte("\n}\n"))
+	if end < 0 {
+		return config
+	}
+	end += start + len("\n}\n")
+	return append(config[:start:start], config[end:]...)
+}
+
+// getIDsFromConfig populates a map with existing ids found in the given config file
 func getIDsFromConfig(configPath string) (map[uint16]string, error) {
-	ids := map[uint16]string{}
 	config, err := os.ReadFile(configPath)
 	if err != nil {
-		return ids, err
+		return map[uint16]string{}, err
 	}
+	return parseExportIDs(config), nil
+}
 
+// parseExportIDs returns the ids of the exports marked with their volume
+func parseExportIDs(config []byte) map[uint16]string {
+	ids := map[uint16]string{}
 	allMatches := exportRegex.FindAllSubmatch(config, -1)
 	for _, match := range allMatches {
 		// there should be the full match a submatch for id and volume
@@ -213,46 +438,17 @@ func getIDsFromConfig(configPath string) (map[uint16]string, error) {
 		}
 	}
 
-	return ids, nil
-}
-
-func (e *Exporter) addToConfig(block string) error {
-	e.fileMutex.Lock()
-	defer e.fileMutex.Unlock()
-
-	config, err := os.OpenFile(e.configPath, os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if errClose := config.Close(); errClose != nil {
-			logrus.WithError(errClose).Error("Failed to close config file")
-		}
-	}()
-
-	if _, err = config.WriteString(block); err != nil {
-		return err
-	}
-	if err = config.Sync(); err != nil {
-		return err
-	}
-	return nil
+	return ids
 }
 
-func (e *Exporter) removeFromConfig(block string) error {
-	e.fileMutex.Lock()
-	defer e.fileMutex.Unlock()
-
-	config, err := os.ReadFile(e.configPath)
-	if err != nil {
-		return err
-	}
+// loadIDs replaces the export map with the ids found in the config
+func (e *Exporter) loadIDs(config []byte) {
+	e.mapMutex.Lock()
+	defer e.mapMutex.Unlock()
 
-	newConfig := strings.ReplaceAll(string(config), block, "")
-	err = os.WriteFile(e.configPath, []byte(newConfig), 0)
-	if err != nil {
-		return err
+	e.idToVolume = parseExportIDs(config)
+	e.volumeToid = map[string]uint16{}
+	for id, vol := range e.idToVolume {
+		e.volumeToid[vol] = id
 	}
-
-	return nil
 }
@@ -0,0 +1,96 @@
+package nfs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRemoveExportBlock(t *testing.T) {
+	volume := generateExportBlock("/export", "pvc-1", 1, "4", DefaultExportOptions)
+	subExport := generateSubExportBlock("/export", "pvc-1", SubExport{Name: "data", Path: "data", Pseudo: "/pvc-1/data",
+		ExportOptions: ExportOptions{AccessType: "RO", Squash: "All", Clients: []string{"10.0.0.0/8"}}}, 2, "4")
+	header := "NFS_Core_Param {\n\tProtocols = 4;\n}\n"
+
+	tests := []struct {
+		name   string
+		config string
+		key    string
+		id     uint16
+		want   string
+	}{
+		{name: "volume export", config: header + volume + subExport, key: "pvc-1", id: 1, want: header + subExport},
+		{name: "sub-export with client block", config: header + volume + subExport, key: "pvc-1/data", id: 2, want: header + volume},
+		{name: "id does not match", config: header + volume, key: "pvc-1", id: 2, want: header + volume},
+		{name: "key does not match", config: header + volume, key: "pvc-10", id: 1, want: header + volume},
+		{name: "block is not closed", config: header + volume[:len(volume)-2], key: "pvc-1", id: 1, want: header + volume[:len(volume)-2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(removeExportBlock([]byte(tt.config), tt.key, tt.id)); got != tt.want {
+				t.Errorf("removeExportBlock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExporterIDs(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "vfs.conf")
+	if err := os.WriteFile(configPath, []byte("NFS_Core_Param {\n\tProtocols = 4;\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	exporter, err := NewExporter(configPath, "/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subExport := SubExport{Name: "data", Path: "data", Pseudo: "/pvc-1/data", ExportOptions: DefaultExportOptions}
+	steps := []struct {
+		name    string
+		create  func() (uint16, error)
+		want    uint16
+		wantErr bool
+	}{
+		{name: "sub-export", create: func() (uint16, error) { return exporter.CreateSubExport("pvc-1", subExport, 7) }, want: 7},
+		{name: "volume", create: func() (uint16, error) { return exporter.CreateExport("pvc-1") }, want: 1},
+		{name: "volume again", create: func() (uint16, error) { return exporter.CreateExport("pvc-1") }, want: 1},
+		{name: "sub-export again", create: func() (uint16, error) { return exporter.CreateSubExport("pvc-1", subExport, 7) }, want: 7},
+		{name: "snapshot with used id", create: func() (uint16, error) {
+			return exporter.CreateSnapshotExport("pvc-1", "snap", 7, DefaultExportOptions)
+		}, wantErr: true},
+		{name: "snapshot", create: func() (uint16, error) {
+			return exporter.CreateSnapshotExport("pvc-1", "snap", 8, DefaultExportOptions)
+		}, want: 8},
+	}
+	for _, step := range steps {
+		id, err := step.create()
+		if (err != nil) != step.wantErr {
+			t.Fatalf("%v: error = %v, wantErr %v", step.name, err, step.wantErr)
+		}
+		if id != step.want {
+			t.Errorf("%v: export id = %v, want %v", step.name, id, step.want)
+		}
+	}
+
+	config, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint16]string{1: "pvc-1", 7: "pvc-1/data", 8: "pvc-1/.snapshots/snap"}
+	if got := parseExportIDs(config); !reflect.DeepEqual(got, want) {
+		t.Errorf("export ids in config = %v, want %v", got, want)
+	}
+
+	if err := exporter.DeleteSubExports("pvc-1"); err != nil {
+		t.Fatal(err)
+	}
+	config, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parseExportIDs(config); !reflect.DeepEqual(got, map[uint16]string{1: "pvc-1"}) {
+		t.Errorf("export ids in config after deleting the sub-exports = %v, want only the volume", got)
+	}
+}
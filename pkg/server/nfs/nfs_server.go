@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
+	"strconv"
 	"syscall"
 	"text/template"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/sirupsen/logrus"
@@ -15,20 +19,137 @@ import (
 
 const (
 	defaultPidFile = "/var/run/ganesha.pid"
+
+	// stopTimeout is how long ganesha gets to shut down cleanly after SIGTERM before it is killed
+	stopTimeout = 10 * time.Second
+
+	DefaultMountPort = 20048
+	DefaultNLMPort   = 32803
+	DefaultStatdPort = 662
+	// DefaultRQUOTAPort is the conventional port of rquotad
+	DefaultRQUOTAPort = 875
+
+	// PortmapperPort is where NFSv3 clients look up the ports of the MOUNT, NLM, NSM and RQUOTA services
+	PortmapperPort = 111
+
+	rpcbindSocket = "/run/rpcbind.sock"
+
+	// serviceStartTimeout is how long rpcbind and dbus-daemon get to come up before ganesha
+	// registers with them
+	serviceStartTimeout = 10 * time.Second
+
+	// drainPollInterval is how often the completed operations are counted while draining
+	drainPollInterval = time.Second
+
+	// RecoveryBackendLonghorn keeps the client recovery records in longhorn-manager,
+	// it requires the Longhorn build of ganesha
+	RecoveryBackendLonghorn = "longhorn"
+	// RecoveryBackendFS keeps the client recovery records in a directory, in the state
+	// directory of the volume they follow it across nodes
+	RecoveryBackendFS = "fs"
 )
 
+// ConfigOptions are the settings of the generated nfs server config
+type ConfigOptions struct {
+	LeaseLifetime int
+	// GracePeriod is the longest grace period after a failover. Ganesha ends it early once every
+	// client in the records of the recovery backend sent RECLAIM_COMPLETE, but only while NLM is
+	// disabled, since NFSv3 clients have no such signal. The share manager does not lift it itself.
+	GracePeriod int
+
+	// EnableNFSv3 serves NFSv3 next to NFSv4, with the MOUNT, NLM locking, NSM status and
+	// RQUOTA services on fixed ports behind the portmapper, so they can be exposed by the pod.
+	// RQUOTA lets NFSv3 clients query their quota with quota(1), NFSv4 has no such service.
+	EnableNFSv3 bool
+	MountPort   int
+	NLMPort     int
+	StatdPort   int
+	RQUOTAPort  int
+
+	Tuning Tuning
+
+	RecoveryBackend string
+	// RecoveryRoot is the directory of the records of the fs recovery backend
+	RecoveryRoot string
+}
+
+// Protocols returns the NFS versions to serve in the format of the config
+func (o ConfigOptions) Protocols() string {
+	if o.EnableNFSv3 {
+		return "3, 4"
+	}
+	return "4"
+}
+
+// ValidateRecoveryBackend checks that the recovery backend is supported
+func ValidateRecoveryBackend(backend string) error {
+	if backend != RecoveryBackendLonghorn && backend != RecoveryBackendFS {
+		return errors.Errorf("unknown recovery backend %q, expected %v or %v", backend, RecoveryBackendLonghorn, RecoveryBackendFS)
+	}
+	return nil
+}
+
+// Port is a port the nfs server listens on
+type Port struct {
+	Service string
+	Port    int
+}
+
+// Ports returns the ports the nfs server listens on with these options
+func (o ConfigOptions) Ports() []Port {
+	ports := []Port{{Service: "nfs", Port: NFSPort}}
+	if o.EnableNFSv3 {
+		ports = append(ports,
+			Port{Service: "portmapper", Port: PortmapperPort},
+			Port{Service: "mount", Port: o.MountPort},
+			Port{Service: "nlm", Port: o.NLMPort},
+			Port{Service: "statd", Port: o.StatdPort},
+			Port{Service: "rquota", Port: o.RQUOTAPort})
+	}
+	return ports
+}
+
 var defaultConfig = []byte(`
 NFS_Core_Param
 {
-    NLM_Port = 0;
-    MNT_Port = 0;
-    RQUOTA_Port = 0;
-    Enable_NLM = false;
-    Enable_RQUOTA = false;
+    NLM_Port = {{if .EnableNFSv3}}{{.NLMPort}}{{else}}0{{end}};
+    MNT_Port = {{if .EnableNFSv3}}{{.MountPort}}{{else}}0{{end}};
+    RQUOTA_Port = {{if .EnableNFSv3}}{{.RQUOTAPort}}{{else}}0{{end}};
+    Enable_NLM = {{.EnableNFSv3}};
+    Enable_RQUOTA = {{.EnableNFSv3}};
     Enable_UDP = false;
+    Enable_NFSSTATS = true;
     fsid_device = false;
-    Protocols = 4;
+    Protocols = {{.Protocols}};
+{{- with .Tuning}}
+{{- if .WorkerThreads}}
+    Nb_Worker = {{.WorkerThreads}};
+{{- end}}
+{{- if .MaxConnections}}
+    RPC_Max_Connections = {{.MaxConnections}};
+{{- end}}
+{{- if .MaxRequests}}
+    Dispatch_Max_Reqs = {{.MaxRequests}};
+{{- end}}
+{{- end}}
+}
+{{- if .Tuning.HasCacheTuning}}
+
+MDCACHE
+{
+{{- with .Tuning}}
+{{- if .CacheEntries}}
+    Entries_HWMark = {{.CacheEntries}};
+{{- end}}
+{{- if .CacheChunks}}
+    Chunks_HWMark = {{.CacheChunks}};
+{{- end}}
+{{- if .DirChunk}}
+    Dir_Chunk = {{.DirChunk}};
+{{- end}}
+{{- end}}
 }
+{{- end}}
 
 LOG {
 	Default_Log_Level = INFO;
@@ -48,13 +169,17 @@ NFSV4
     Lease_Lifetime = {{.LeaseLifetime}};
     Grace_Period = {{.GracePeriod}};
     Minor_Versions = 0, 1, 2;
-    RecoveryBackend = longhorn;
+    RecoveryBackend = {{.RecoveryBackend}};
+{{- if .RecoveryRoot}}
+    RecoveryRoot = "{{.RecoveryRoot}}";
+{{- end}}
     Only_Numeric_Owners = true;
+    Delegations = false;
 }
 
 Export_defaults
 {
-    Protocols = 4;
+    Protocols = {{.Protocols}};
     Transports = TCP;
     Access_Type = None;
     SecType = sys;
@@ -81,16 +206,17 @@ type Server struct {
 	logger     logrus.FieldLogger
 	configPath string
 	exportPath string
+	options    ConfigOptions
 	exporter   *Exporter
 }
 
-func NewServer(logger logrus.FieldLogger, configPath, exportPath, volume string, leaseLifetime, gracePeriod int) (*Server, error) {
+func NewServer(logger logrus.FieldLogger, configPath, exportPath, volume string, options ConfigOptions) (*Server, error) {
 	if err := setRlimitNOFILE(logger); err != nil {
 		logger.WithError(err).Warn("Error setting RLIMIT_NOFILE, there may be 'Too many open files' errors later")
 	}
 
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		if err = os.WriteFile(configPath, getUpdatedGaneshConfig(defaultConfig, leaseLifetime, gracePeriod), 0600); err != nil {
+		if err = writeConfig(configPath, getUpdatedGaneshConfig(defaultConfig, options)); err != nil {
 			return nil, errors.Wrapf(err, "error writing nfs config %s", configPath)
 		}
 	}
@@ -104,26 +230,175 @@ func NewServer(logger logrus.FieldLogger, configPath, exportPath, volume string,
 		logger:     logger,
 		configPath: configPath,
 		exportPath: exportPath,
+		options:    options,
 		exporter:   exporter,
 	}, nil
 }
 
+// Ports returns the ports the nfs server listens on
+func (s *Server) Ports() []Port {
+	return s.options.Ports()
+}
+
 func (s *Server) CreateExport(volume string) (uint16, error) {
 	return s.exporter.CreateExport(volume)
 }
 
+func (s *Server) DeleteExport(volume string) error {
+	return s.exporter.DeleteExport(volume)
+}
+
+func (s *Server) CreateSubExport(volume string, subExport SubExport, exportID uint16) (uint16, error) {
+	return s.exporter.CreateSubExport(volume, subExport, exportID)
+}
+
+func (s *Server) GetSubExport(volume, name string) uint16 {
+	return s.exporter.GetSubExport(volume, name)
+}
+
+func (s *Server) DeleteSubExport(volume, name string) error {
+	return s.exporter.DeleteSubExport(volume, name)
+}
+
+func (s *Server) CreateSnapshotExport(volume, name string, exportID uint16, options ExportOptions) (uint16, error) {
+	return s.exporter.CreateSnapshotExport(volume, name, exportID, options)
+}
+
+func (s *Server) GetSnapshotExport(volume, name string) uint16 {
+	return s.exporter.GetSnapshotExport(volume, name)
+}
+
+func (s *Server) DeleteSnapshotExport(volume, name string) error {
+	return s.exporter.DeleteSnapshotExport(volume, name)
+}
+
+func (s *Server) DeleteSubExports(volume string) error {
+	return s.exporter.DeleteSubExports(volume)
+}
+
+func (s *Server) ReloadExport(ctx context.Context) error {
+	return s.exporter.ReloadExport(ctx)
+}
+
 func (s *Server) Run(ctx context.Context) error {
+	if err := s.startDBus(ctx); err != nil {
+		return err
+	}
+	if s.options.EnableNFSv3 {
+		if err := s.startRPCServices(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Start ganesha.nfsd
 	s.logger.Info("Running NFS server!")
 	cmd := exec.CommandContext(ctx, "ganesha.nfsd", "-F", "-p", defaultPidFile, "-f", s.configPath)
+	// let ganesha flush its state on cancellation, it is only killed if it does not exit in time
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = stopTimeout
 
 	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil && cmd.ProcessState != nil && cmd.ProcessState.Success() {
+			s.logger.Info("NFS server stopped")
+			return nil
+		}
 		return fmt.Errorf("ganesha.nfsd failed with error: %v, output: %s", err, out)
 	}
 
 	return nil
 }
 
+// startRPCServices starts the portmapper and the NSM status monitor, NFSv3 clients need them to
+// find the MOUNT, NLM and RQUOTA services and to recover their locks. Ganesha registers its services
+// with the portmapper on start, so rpcbind has to be up first. Both stop with the context.
+func (s *Server) startRPCServices(ctx context.Context) error {
+	const impact = "NFSv3 clients cannot reach the nfs server"
+	if err := s.startService(ctx, exec.CommandContext(ctx, "rpcbind", "-f", "-w"), impact); err != nil {
+		return err
+	}
+	if err := waitForSocket(ctx, rpcbindSocket, serviceStartTimeout); err != nil {
+		return errors.Wrap(err, "rpcbind did not start")
+	}
+	return s.startService(ctx, exec.CommandContext(ctx, "rpc.statd", "-F", "-p", strconv.Itoa(s.options.StatdPort)), impact)
+}
+
+// startDBus starts the system bus, ganesha registers its management interface on it on start,
+// through which the nfs server is drained. A socket left by an earlier run is removed first.
+func (s *Server) startDBus(ctx context.Context) error {
+	if err := os.Remove(dbusSocket); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove the socket of an earlier system bus")
+	}
+	cmd := exec.CommandContext(ctx, "dbus-daemon", "--system", "--nofork", "--nopidfile")
+	if err := s.startService(ctx, cmd, "the nfs server cannot be drained"); err != nil {
+		return err
+	}
+	if err := waitForSocket(ctx, dbusSocket, serviceStartTimeout); err != nil {
+		return errors.Wrap(err, "dbus-daemon did not start")
+	}
+	return nil
+}
+
+// startService starts a service which runs next to ganesha, impact tells what is lost if it exits
+func (s *Server) startService(ctx context.Context, cmd *exec.Cmd, impact string) error {
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.WaitDelay = stopTimeout
+	if err := cmd.Start(); err != nil {
+		return errors.Wrapf(err, "failed to start %v", cmd.Path)
+	}
+
+	go func() {
+		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).Errorf("%v exited, %v", cmd.Path, impact)
+		}
+	}()
+	return nil
+}
+
+func waitForSocket(ctx context.Context, path string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "timed out waiting for %v", path)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain removes all exports from the running nfs server, so no client can mount the volume or
+// start new operations on it, and waits until the outstanding operations are done or ctx is done.
+// Removing an export releases the opens and locks of its clients, delegations are disabled in the
+// config so there are none to recall. The exports stay in the config. Draining requires the DBus
+// interface of ganesha, ErrDBusUnavailable is returned without it.
+func (s *Server) Drain(ctx context.Context) error {
+	exports := s.exporter.GetExportMap()
+	// the sub-exports and snapshots have higher ids than the volume and are removed first
+	ids := slices.Sorted(maps.Keys(exports.idToVolume))
+	slices.Reverse(ids)
+
+	var errs error
+	for _, id := range ids {
+		if err := removeExport(ctx, id); err != nil {
+			if errors.Is(err, ErrDBusUnavailable) {
+				return err
+			}
+			errs = errors.CombineErrors(errs, errors.Wrapf(err, "failed to remove export %v of %v", id, exports.idToVolume[id]))
+		}
+	}
+
+	return errors.CombineErrors(errs, waitForIdle(ctx, drainPollInterval, globalOps))
+}
+
 func setRlimitNOFILE(logger logrus.FieldLogger) error {
 	var rlimit syscall.Rlimit
 	err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlimit)
@@ -145,7 +420,7 @@ func setRlimitNOFILE(logger logrus.FieldLogger) error {
 	return nil
 }
 
-func getUpdatedGaneshConfig(config []byte, leaseLifetime, gracePeriod int) []byte {
+func getUpdatedGaneshConfig(config []byte, options ConfigOptions) []byte {
 	var (
 		tmplBuf bytes.Buffer
 		logPath string
@@ -157,14 +432,16 @@ func getUpdatedGaneshConfig(config []byte, leaseLifetime, gracePeriod int) []byt
 		logPath = "/tmp/ganesha.log"
 	}
 
+	if options.RecoveryBackend == "" {
+		options.RecoveryBackend = RecoveryBackendLonghorn
+	}
+
 	tmplVals := struct {
-		LogPath       string
-		LeaseLifetime int
-		GracePeriod   int
+		ConfigOptions
+		LogPath string
 	}{
+		ConfigOptions: options,
 		LogPath:       logPath,
-		LeaseLifetime: leaseLifetime,
-		GracePeriod:   gracePeriod,
 	}
 
 	if err := template.Must(template.New("Ganesha_Config").Parse(string(config))).Execute(&tmplBuf, tmplVals); err != nil {
@@ -0,0 +1,104 @@
+package nfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetUpdatedGaneshaConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ConfigOptions
+		want    map[string]string
+	}{
+		{
+			name:    "NFSv4 only",
+			options: ConfigOptions{LeaseLifetime: 60, GracePeriod: 90},
+			want:    map[string]string{"Protocols": "4", "Enable_NLM": "false", "NLM_Port": "0", "MNT_Port": "0", "Enable_RQUOTA": "false", "RQUOTA_Port": "0", "Enable_NFSSTATS": "true"},
+		},
+		{
+			name: "NFSv3 with NLM and RQUOTA",
+			options: ConfigOptions{LeaseLifetime: 60, GracePeriod: 90, EnableNFSv3: true, MountPort: DefaultMountPort, NLMPort: DefaultNLMPort,
+				StatdPort: DefaultStatdPort, RQUOTAPort: DefaultRQUOTAPort},
+			want: map[string]string{"Protocols": "3, 4", "Enable_NLM": "true", "NLM_Port": "32803", "MNT_Port": "20048",
+				"Enable_RQUOTA": "true", "RQUOTA_Port": "875"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := getUpdatedGaneshConfig(defaultConfig, tt.options)
+			blocks, err := ParseConfig(config)
+			if err != nil {
+				t.Fatalf("failed to parse rendered config: %v\n%s", err, config)
+			}
+
+			var core *ConfigBlock
+			for _, block := range blocks {
+				if block.Name == "NFS_Core_Param" {
+					core = block
+				}
+			}
+			if core == nil {
+				t.Fatalf("rendered config has no NFS_Core_Param block\n%s", config)
+			}
+			for key, want := range tt.want {
+				if param, _ := core.Param(key); param.Value != want {
+					t.Errorf("NFS_Core_Param %v = %q, want %q", key, param.Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigOptionsPorts(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ConfigOptions
+		want    []Port
+	}{
+		{name: "NFSv4 only", want: []Port{{Service: "nfs", Port: NFSPort}}},
+		{
+			name: "NFSv3",
+			options: ConfigOptions{EnableNFSv3: true, MountPort: DefaultMountPort, NLMPort: DefaultNLMPort, StatdPort: DefaultStatdPort,
+				RQUOTAPort: DefaultRQUOTAPort},
+			want: []Port{
+				{Service: "nfs", Port: NFSPort},
+				{Service: "portmapper", Port: PortmapperPort},
+				{Service: "mount", Port: DefaultMountPort},
+				{Service: "nlm", Port: DefaultNLMPort},
+				{Service: "statd", Port: DefaultStatdPort},
+				{Service: "rquota", Port: DefaultRQUOTAPort},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.Ports(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ports() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRecoveryBackend(t *testing.T) {
+	tests := []struct {
+		backend string
+		wantErr bool
+	}{
+		{backend: RecoveryBackendLonghorn},
+		{backend: RecoveryBackendFS},
+		{backend: "rados_kv", wantErr: true},
+		{backend: "FS", wantErr: true},
+		{backend: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			if err := ValidateRecoveryBackend(tt.backend); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRecoveryBackend(%q) error = %v, wantErr %v", tt.backend, err, tt.wantErr)
+			}
+		})
+	}
+}
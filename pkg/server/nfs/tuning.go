@@ -0,0 +1,66 @@
+package nfs
+
+import (
+	"math"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/util"
+)
+
+// The derived settings never go below the ganesha defaults, a container with a small limit is
+// served as before. Larger limits scale them up.
+const (
+	workerThreadsPerCPU  = 32
+	defaultWorkerThreads = 256 // the ganesha default of Nb_Worker
+	maxWorkerThreads     = 1024
+
+	// cacheEntrySize is a rough estimate of the memory an MDCACHE entry takes, the cache
+	// may use a quarter of the memory limit
+	cacheEntrySize      = 2048
+	cacheMemoryFraction = 4
+	defaultCacheEntries = 100000 // the ganesha default of Entries_HWMark
+	maxCacheEntries     = 10000000
+)
+
+// Tuning are the worker, RPC and metadata cache settings of the nfs server, 0 keeps the
+// ganesha default. The MDCACHE block was called CACHEINODE in older ganesha versions.
+type Tuning struct {
+	// WorkerThreads is Nb_Worker of NFS_Core_Param
+	WorkerThreads int
+	// MaxConnections is RPC_Max_Connections of NFS_Core_Param
+	MaxConnections int
+	// MaxRequests is Dispatch_Max_Reqs of NFS_Core_Param
+	MaxRequests int
+	// CacheEntries is Entries_HWMark of MDCACHE, the number of cached files and directories
+	CacheEntries int
+	// CacheChunks is Chunks_HWMark of MDCACHE, the number of cached directory chunks
+	CacheChunks int
+	// DirChunk is Dir_Chunk of MDCACHE, the number of directory entries per chunk
+	DirChunk int
+}
+
+// HasCacheTuning returns whether the MDCACHE block has any setting
+func (t Tuning) HasCacheTuning() bool {
+	return t.CacheEntries > 0 || t.CacheChunks > 0 || t.DirChunk > 0
+}
+
+// DeriveTuning fills the worker threads and cache entries which are not set explicitly from
+// the CPU and memory limits of the container. Without limits the ganesha defaults are kept.
+func DeriveTuning(tuning Tuning) Tuning {
+	cpus, _ := util.GetCgroupCPULimit()
+	memory, _ := util.GetCgroupMemoryLimit()
+	return deriveTuning(tuning, cpus, memory)
+}
+
+// deriveTuning derives the tuning from a CPU limit in CPUs and a memory limit in bytes,
+// 0 is unlimited
+func deriveTuning(tuning Tuning, cpus float64, memory uint64) Tuning {
+	if tuning.WorkerThreads == 0 && cpus > 0 {
+		tuning.WorkerThreads = min(max(int(math.Ceil(cpus))*workerThreadsPerCPU, defaultWorkerThreads), maxWorkerThreads)
+	}
+
+	if tuning.CacheEntries == 0 && memory > 0 {
+		tuning.CacheEntries = int(min(max(memory/cacheMemoryFraction/cacheEntrySize, defaultCacheEntries), maxCacheEntries))
+	}
+
+	return tuning
+}
@@ -0,0 +1,32 @@
+package nfs
+
+import "testing"
+
+func TestDeriveTuning(t *testing.T) {
+	tests := []struct {
+		name   string
+		tuning Tuning
+		cpus   float64
+		memory uint64
+		want   Tuning
+	}{
+		{name: "unlimited keeps the ganesha defaults"},
+		{name: "small limits stay at the ganesha defaults", cpus: 0.5, memory: 256 << 20,
+			want: Tuning{WorkerThreads: defaultWorkerThreads, CacheEntries: defaultCacheEntries}},
+		{name: "large limits exceed the ganesha defaults", cpus: 16, memory: 4 << 30,
+			want: Tuning{WorkerThreads: 512, CacheEntries: 524288}},
+		{name: "huge limits are capped", cpus: 128, memory: 1 << 40,
+			want: Tuning{WorkerThreads: maxWorkerThreads, CacheEntries: maxCacheEntries}},
+		{name: "fractional CPUs are rounded up", cpus: 8.5, want: Tuning{WorkerThreads: 288}},
+		{name: "explicit values are kept", tuning: Tuning{WorkerThreads: 8, CacheEntries: 1000, DirChunk: 64}, cpus: 16, memory: 4 << 30,
+			want: Tuning{WorkerThreads: 8, CacheEntries: 1000, DirChunk: 64}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deriveTuning(tt.tuning, tt.cpus, tt.memory); got != tt.want {
+				t.Errorf("deriveTuning(%+v, %v, %v) = %+v, want %+v", tt.tuning, tt.cpus, tt.memory, got, tt.want)
+			}
+		})
+	}
+}
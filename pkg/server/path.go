@@ -0,0 +1,82 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+)
+
+var ErrInvalidPath = errors.New("invalid path")
+
+// normalizeVolumePath cleans a path relative to the mount path of the volume. It has to name
+// a subdirectory, which is not part of the state or the snapshots of the share manager.
+func normalizeVolumePath(path string) (string, error) {
+	path = filepath.Clean(path)
+	if !filepath.IsLocal(path) || path == "." {
+		return path, errors.Wrapf(ErrInvalidPath, "path %q must be a subdirectory of the volume", path)
+	}
+	if first, _, _ := strings.Cut(path, string(filepath.Separator)); first == types.StateDirName || first == types.SnapshotsDirName {
+		return path, errors.Wrapf(ErrInvalidPath, "path %q is reserved for the share manager", path)
+	}
+	return path, nil
+}
+
+// checkDirectoryInVolume makes sure the directory exists and does not resolve to a path
+// outside of the volume through a symlink
+func checkDirectoryInVolume(volumeName, path string) error {
+	return checkDirectoryInMount(types.GetMountPath(volumeName), path)
+}
+
+// checkPathInVolume resolves the symlinks of the longest existing part of the path and makes sure
+// it stays below the mount path of the volume
+func checkPathInVolume(volumeName, path string) error {
+	return checkPathInMount(types.GetMountPath(volumeName), path)
+}
+
+func checkDirectoryInMount(mountPath, path string) error {
+	if err := checkPathInMount(mountPath, path); err != nil {
+		return err
+	}
+
+	fullPath := filepath.Join(mountPath, path)
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.Wrapf(ErrInvalidPath, "path %v is not a directory", path)
+	}
+	return nil
+}
+
+func checkPathInMount(mountPath, path string) error {
+	resolvedMountPath, err := filepath.EvalSymlinks(mountPath)
+	if err != nil {
+		return err
+	}
+
+	fullPath := filepath.Join(mountPath, path)
+	existing := fullPath
+	for existing != mountPath {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		existing = filepath.Dir(existing)
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve path %v", path)
+	}
+	if rel, err := filepath.Rel(resolvedMountPath, resolved); err != nil || !filepath.IsLocal(rel) {
+		return errors.Wrapf(ErrInvalidPath, "path %v resolves to %v outside of the volume", path, resolved)
+	}
+	if resolved == resolvedMountPath && existing == fullPath {
+		return errors.Wrapf(ErrInvalidPath, "path %v resolves to the root of the volume", path)
+	}
+	return nil
+}
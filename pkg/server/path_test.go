@@ -0,0 +1,98 @@
+package server
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeVolumePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{name: "subdirectory", path: "data", want: "data"},
+		{name: "nested", path: "data/./logs/", want: "data/logs"},
+		{name: "parent inside the volume", path: "data/../logs", want: "logs"},
+		{name: "root", path: ".", wantErr: true},
+		{name: "empty", path: "", wantErr: true},
+		{name: "absolute", path: "/data", wantErr: true},
+		{name: "escapes the volume", path: "../data", wantErr: true},
+		{name: "state directory", path: ".longhorn-share-manager/recovery", wantErr: true},
+		{name: "snapshots", path: ".snapshots", wantErr: true},
+		{name: "name starting like the state directory", path: ".longhorn-share-manager-data", want: ".longhorn-share-manager-data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeVolumePath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeVolumePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPath) {
+					t.Errorf("normalizeVolumePath(%q) error = %v, want %v", tt.path, err, ErrInvalidPath)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("normalizeVolumePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPathInMount(t *testing.T) {
+	root := t.TempDir()
+	mountPath := filepath.Join(root, "vol")
+	outside := filepath.Join(root, "outside")
+	for _, dir := range []string{filepath.Join(mountPath, "data", "logs"), outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(mountPath, "file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{
+		"inside":   "data",
+		"escape":   outside,
+		"relative": "../outside",
+		"root":     ".",
+	} {
+		if err := os.Symlink(target, filepath.Join(mountPath, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantErr    bool
+		wantDirErr bool
+	}{
+		{name: "directory", path: "data/logs"},
+		{name: "missing directory", path: "data/new/dir", wantDirErr: true},
+		{name: "symlink inside the volume", path: "inside/logs"},
+		{name: "file", path: "file", wantDirErr: true},
+		{name: "symlink out of the volume", path: "escape", wantErr: true},
+		{name: "missing directory below a symlink out of the volume", path: "escape/new", wantErr: true},
+		{name: "relative symlink out of the volume", path: "relative/new", wantErr: true},
+		{name: "symlink to the root", path: "root", wantErr: true},
+		{name: "missing directory below a symlink to the root", path: "root/new", wantDirErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPathInMount(mountPath, tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("checkPathInMount(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if err := checkDirectoryInMount(mountPath, tt.path); (err != nil) != (tt.wantErr || tt.wantDirErr) {
+				t.Errorf("checkDirectoryInMount(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr || tt.wantDirErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+
+	"github.com/cockroachdb/errors"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/quota"
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+	"github.com/longhorn/longhorn-share-manager/pkg/volume"
+)
+
+var (
+	ErrProjectQuotaDisabled = errors.New("project quotas are disabled")
+	ErrInvalidProject       = errors.New("invalid project")
+)
+
+// ProjectQuotaEnabled returns whether the volume is mounted with project quotas
+func (m *ShareManager) ProjectQuotaEnabled() bool {
+	return m.enableProjectQuota
+}
+
+// projectQuotaOptions adds the options enabling project quotas on ext4 and xfs. A new ext4
+// filesystem is formatted with the project feature, an existing one gets it with tune2fs,
+// which requires the filesystem to be unmounted. If the filesystem cannot support project
+// quotas, the volume is mounted without.
+func (m *ShareManager) projectQuotaOptions(ctx context.Context, devicePath, mountPath, fsType, diskFormat string,
+	mountOptions, formatOptions []string) ([]string, []string) {
+	switch fsType {
+	case "ext4":
+		if diskFormat == "" {
+			formatOptions = append(slices.Clone(formatOptions), "-O", "quota,project", "-E", "quotatype=prjquota")
+		} else if !volume.CheckMountValid(mountPath) {
+			if err := volume.EnableExt4ProjectQuota(ctx, devicePath); err != nil {
+				m.logger.WithError(err).Errorf("Failed to enable project quotas on %v, mounting without", devicePath)
+				return mountOptions, formatOptions
+			}
+		}
+	case "xfs":
+	default:
+		m.logger.Warnf("Project quotas are not supported on %v filesystems, mounting without", fsType)
+		return mountOptions, formatOptions
+	}
+
+	return append(slices.Clone(mountOptions), quota.MountOption), formatOptions
+}
+
+// CheckProjectDirectory returns the normalized path of a directory of the volume which can be
+// assigned to a project
+func (m *ShareManager) CheckProjectDirectory(path string) (string, error) {
+	if !m.enableProjectQuota {
+		return path, ErrProjectQuotaDisabled
+	}
+
+	path, err := normalizeVolumePath(path)
+	if err != nil {
+		return path, err
+	}
+	return path, checkDirectoryInVolume(m.volume.Name, path)
+}
+
+// SetDirectoryProject assigns a directory of the volume and everything below it to the project,
+// it returns early once ctx is cancelled
+func (m *ShareManager) SetDirectoryProject(ctx context.Context, path string, id uint32) (count int, err error) {
+	ctx, span := tracing.Start(ctx, "ShareManager.SetDirectoryProject",
+		attribute.String("path", path), attribute.Int64("projectID", int64(id)))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if path, err = m.CheckProjectDirectory(path); err != nil {
+		return 0, err
+	}
+
+	count, err = quota.SetProjectID(ctx, filepath.Join(types.GetMountPath(m.volume.Name), path), id)
+	if err != nil {
+		return count, err
+	}
+
+	m.logger.Infof("Assigned directory %v with %v entries to project %v", path, count, id)
+	return count, nil
+}
+
+// SetProjectQuota sets the limits of the project and returns its quota
+func (m *ShareManager) SetProjectQuota(ctx context.Context, id uint32, limits quota.Quota) (_ *quota.Quota, err error) {
+	_, span := tracing.Start(ctx, "ShareManager.SetProjectQuota", attribute.Int64("projectID", int64(id)))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if !m.enableProjectQuota {
+		return nil, ErrProjectQuotaDisabled
+	}
+	if id == 0 {
+		return nil, errors.Wrap(ErrInvalidProject, "project 0 holds all files without a project and cannot be limited")
+	}
+
+	if err := quota.SetProjectQuota(m.quotaDevicePath(), id, limits); err != nil {
+		return nil, err
+	}
+
+	m.logger.Infof("Set limits of project %v to %v bytes and %v inodes", id, limits.BlockHardLimit, limits.InodeHardLimit)
+	return quota.GetProjectQuota(m.quotaDevicePath(), id)
+}
+
+// GetProjectQuota returns the quota of the project, or of the project of the directory if a path is given
+func (m *ShareManager) GetProjectQuota(ctx context.Context, id uint32, path string) (_ uint32, _ *quota.Quota, err error) {
+	_, span := tracing.Start(ctx, "ShareManager.GetProjectQuota", attribute.Int64("projectID", int64(id)))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if !m.enableProjectQuota {
+		return 0, nil, ErrProjectQuotaDisabled
+	}
+
+	if path != "" {
+		if path, err = normalizeVolumePath(path); err != nil {
+			return 0, nil, err
+		}
+		if err := checkDirectoryInVolume(m.volume.Name, path); err != nil {
+			return 0, nil, err
+		}
+		if id, err = quota.GetProjectID(filepath.Join(types.GetMountPath(m.volume.Name), path)); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	projectQuota, err := quota.GetProjectQuota(m.quotaDevicePath(), id)
+	return id, projectQuota, err
+}
+
+// quotaDevicePath returns the device the volume is mounted from
+func (m *ShareManager) quotaDevicePath() string {
+	return types.GetVolumeDevicePath(m.volume.Name, m.volume.DataEngine, m.volume.IsEncrypted())
+}
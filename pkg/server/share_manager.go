@@ -7,11 +7,13 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sys/unix"
 
 	"k8s.io/client-go/kubernetes"
@@ -21,10 +23,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
 
-	lhns "github.com/longhorn/go-common-libs/ns"
-
+	"github.com/longhorn/longhorn-share-manager/pkg/audit"
 	"github.com/longhorn/longhorn-share-manager/pkg/crypto"
 	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
 	"github.com/longhorn/longhorn-share-manager/pkg/types"
 	"github.com/longhorn/longhorn-share-manager/pkg/volume"
 )
@@ -40,12 +42,26 @@ const EnvKeyFastFailover = "FAST_FAILOVER"
 const EnvKeyLeaseLifetime = "LEASE_LIFETIME"
 const EnvKeyGracePeriod = "GRACE_PERIOD"
 const EnvKeyFormatOptions = "FS_FORMAT_OPTIONS"
-const defaultLeaseLifetime = 60
-const defaultGracePeriod = 90
+const EnvKeyEnableNFSv3 = "ENABLE_NFSV3"
+const EnvKeyMountPort = "MOUNT_PORT"
+const EnvKeyNLMPort = "NLM_PORT"
+const EnvKeyStatdPort = "STATD_PORT"
+const EnvKeyRQUOTAPort = "RQUOTA_PORT"
+const EnvKeyProjectQuota = "ENABLE_PROJECT_QUOTA"
+const EnvKeyWorkerThreads = "NFS_WORKER_THREADS"
+const EnvKeyMaxConnections = "NFS_MAX_CONNECTIONS"
+const EnvKeyMaxRequests = "NFS_MAX_REQUESTS"
+const EnvKeyCacheEntries = "NFS_CACHE_ENTRIES"
+const EnvKeyCacheChunks = "NFS_CACHE_CHUNKS"
+const EnvKeyDirChunk = "NFS_DIR_CHUNK"
+const EnvKeyRecoveryBackend = "NFS_RECOVERY_BACKEND"
+const DefaultLeaseLifetime = 60
+const DefaultGracePeriod = 90
 
 const (
 	UnhealthyErr = "UNHEALTHY: volume with mount path %v is unhealthy"
 	ReadOnlyErr  = "READONLY: volume with mount path %v is read only"
+	IntegrityErr = "INTEGRITY: volume with mount path %v has %v integrity mismatches"
 )
 
 type ShareManager struct {
@@ -55,30 +71,69 @@ type ShareManager struct {
 
 	shareExported atomic.Bool
 
-	context  context.Context
-	shutdown context.CancelFunc
+	integrityMismatches atomic.Uint64
+
+	context      context.Context
+	shutdown     context.CancelFunc
+	shutdownOnce sync.Once
+
+	auditLogger *audit.Logger
 
 	enableFastFailover bool
+	enableProjectQuota bool
 	leaseHolder        string
 	leaseClient        coordinationv1client.LeasesGetter
 	lease              *coordinationv1.Lease
 
-	nfsServer *nfs.Server
+	nfsServer       *nfs.Server
+	recoveryBackend string
+
+	snapshotMutex sync.Mutex
+	snapshots     map[string]SnapshotExport
+
+	exportIDMutex sync.Mutex
 
 	namespace string
 	podName   string
 }
 
-func NewShareManager(logger logrus.FieldLogger, volume volume.Volume) (*ShareManager, error) {
+func NewShareManager(logger logrus.FieldLogger, volume volume.Volume, auditLogger *audit.Logger, tuning nfs.Tuning) (*ShareManager, error) {
 	m := &ShareManager{
-		volume: volume,
-		logger: logger.WithField("volume", volume.Name).WithField("encrypted", volume.IsEncrypted()),
+		volume:      volume,
+		logger:      logger.WithField("volume", volume.Name).WithField("encrypted", volume.IsEncrypted()),
+		auditLogger: auditLogger,
+		snapshots:   map[string]SnapshotExport{},
 	}
 	m.context, m.shutdown = context.WithCancel(context.Background())
 
 	m.enableFastFailover = m.getEnvAsBool(EnvKeyFastFailover, false)
-	leaseLifetime := m.getEnvAsInt(EnvKeyLeaseLifetime, defaultLeaseLifetime)
-	gracePeriod := m.getEnvAsInt(EnvKeyGracePeriod, defaultGracePeriod)
+	m.enableProjectQuota = m.getEnvAsBool(EnvKeyProjectQuota, false)
+	nfsOptions := nfs.ConfigOptions{
+		LeaseLifetime: m.getEnvAsInt(EnvKeyLeaseLifetime, DefaultLeaseLifetime),
+		GracePeriod:   m.getEnvAsInt(EnvKeyGracePeriod, DefaultGracePeriod),
+		EnableNFSv3:   m.getEnvAsBool(EnvKeyEnableNFSv3, false),
+		Tuning:        nfs.DeriveTuning(tuning),
+	}
+	m.logger.Infof("Tuning nfs server with %+v, unset values keep the ganesha defaults", nfsOptions.Tuning)
+	if nfsOptions.EnableNFSv3 {
+		nfsOptions.MountPort = m.getEnvAsInt(EnvKeyMountPort, nfs.DefaultMountPort)
+		nfsOptions.NLMPort = m.getEnvAsInt(EnvKeyNLMPort, nfs.DefaultNLMPort)
+		nfsOptions.StatdPort = m.getEnvAsInt(EnvKeyStatdPort, nfs.DefaultStatdPort)
+		nfsOptions.RQUOTAPort = m.getEnvAsInt(EnvKeyRQUOTAPort, nfs.DefaultRQUOTAPort)
+		m.logger.Infof("NFSv3 is enabled, the grace period after a failover always lasts %v seconds", nfsOptions.GracePeriod)
+	}
+	nfsOptions.RecoveryBackend = nfs.RecoveryBackendLonghorn
+	if backend := os.Getenv(EnvKeyRecoveryBackend); backend != "" {
+		// falling back to another backend would lose the records of the clients
+		if err := nfs.ValidateRecoveryBackend(backend); err != nil {
+			return nil, errors.Wrapf(err, "invalid %v", EnvKeyRecoveryBackend)
+		}
+		nfsOptions.RecoveryBackend = backend
+	}
+	if nfsOptions.RecoveryBackend == nfs.RecoveryBackendFS {
+		nfsOptions.RecoveryRoot = types.GetRecoveryPath(volume.Name)
+	}
+	m.recoveryBackend = nfsOptions.RecoveryBackend
 
 	// get pod namespace from env
 	namespace := os.Getenv(types.EnvPodNamespace)
@@ -116,7 +171,7 @@ func NewShareManager(logger logrus.FieldLogger, volume volume.Volume) (*ShareMan
 		m.leaseClient = kubeclientset.CoordinationV1()
 	}
 
-	nfsServer, err := nfs.NewServer(logger, configPath, types.ExportPath, volume.Name, leaseLifetime, gracePeriod)
+	nfsServer, err := nfs.NewServer(logger, configPath, types.ExportPath, volume.Name, nfsOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -131,15 +186,18 @@ func (m *ShareManager) Run() error {
 
 	defer func() {
 		// if the server is exiting, try to unmount & teardown device before we terminate the container
-		if err := volume.UnmountVolume(mountPath); err != nil {
+		if err := m.ReleaseSnapshots(m.context); err != nil {
+			m.logger.WithError(err).Error("Failed to release snapshots")
+		}
+		if err := m.UnmountVolume(m.context, mountPath); err != nil {
 			m.logger.WithError(err).Error("Failed to unmount volume")
 		}
 
-		if err := m.tearDownDevice(vol); err != nil {
+		if err := m.tearDownDevice(m.context, vol); err != nil {
 			m.logger.WithError(err).Error("Failed to tear down volume")
 		}
 
-		m.Shutdown()
+		m.Shutdown("nfs server exited")
 	}()
 
 	// Check every waitBetweenChecks for volume attachment. Then run server process once and wait for completion.
@@ -154,26 +212,11 @@ func (m *ShareManager) Run() error {
 				break
 			}
 
-			devicePath, err := m.setupDevice(vol, devicePath)
+			err := m.prepareVolume(vol, devicePath, mountPath)
 			if err != nil {
 				return err
 			}
 
-			if err := m.MountVolume(vol, devicePath, mountPath); err != nil {
-				m.logger.WithError(err).Warn("Failed to mount volume")
-				return err
-			}
-
-			if err := m.resizeVolume(devicePath, mountPath); err != nil {
-				m.logger.WithError(err).Warn("Failed to resize volume after mount")
-				return err
-			}
-
-			if err := volume.SetPermissions(mountPath, 0777); err != nil {
-				m.logger.WithError(err).Error("Failed to set permissions for volume")
-				return err
-			}
-
 			m.logger.Info("Starting nfs server, volume is ready for export")
 
 			if m.enableFastFailover {
@@ -190,6 +233,9 @@ func (m *ShareManager) Run() error {
 				m.logger.WithError(err).Error("Failed to create nfs export")
 				return err
 			}
+			if err := m.ExportSubExports(m.context); err != nil {
+				m.logger.WithError(err).Error("Failed to create nfs exports of sub-exports")
+			}
 
 			m.SetShareExported(true)
 
@@ -202,11 +248,83 @@ func (m *ShareManager) Run() error {
 	}
 }
 
+// prepareVolume sets up the device and mounts it for export
+func (m *ShareManager) prepareVolume(vol volume.Volume, devicePath, mountPath string) (err error) {
+	ctx, span := tracing.Start(m.context, "ShareManager.prepareVolume", attribute.String("volume", vol.Name))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	devicePath, err = m.setupDevice(ctx, vol, devicePath)
+	if err != nil {
+		return err
+	}
+
+	formatted, err := m.MountVolume(ctx, vol, devicePath, mountPath)
+	if err != nil {
+		m.logger.WithError(err).Warn("Failed to mount volume")
+		return err
+	}
+
+	if err := m.resizeVolume(ctx, devicePath, mountPath); err != nil {
+		m.logger.WithError(err).Warn("Failed to resize volume after mount")
+		return err
+	}
+
+	if err := m.prepareRecovery(formatted); err != nil {
+		m.logger.WithError(err).Error("Failed to prepare recovery directory for volume")
+		return err
+	}
+
+	return m.setRootPermissions(vol, mountPath, formatted)
+}
+
+// prepareRecovery creates the directory of the fs recovery backend in the state directory of the
+// volume, out of reach of the clients. The records of a previous filesystem do not belong to the
+// clients of a freshly formatted one.
+func (m *ShareManager) prepareRecovery(formatted bool) error {
+	if m.recoveryBackend != nfs.RecoveryBackendFS {
+		return nil
+	}
+
+	// clients could reach the state directory before it was hidden, anything but a directory
+	// was not created by the share manager
+	recoveryPath := types.GetRecoveryPath(m.volume.Name)
+	if info, err := os.Lstat(recoveryPath); formatted || (err == nil && !info.IsDir()) {
+		if err := os.RemoveAll(recoveryPath); err != nil {
+			return errors.Wrapf(err, "failed to clean recovery directory %v", recoveryPath)
+		}
+	}
+	if err := os.MkdirAll(recoveryPath, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create recovery directory %v", recoveryPath)
+	}
+	return nil
+}
+
+// setRootPermissions applies the configured permissions to the root directory, with the
+// preserve policy only on a freshly formatted filesystem so changes of an admin are kept
+func (m *ShareManager) setRootPermissions(vol volume.Volume, mountPath string, formatted bool) error {
+	permissions := vol.RootPermissions
+	if permissions.Policy == volume.RootPermissionsPreserve && !formatted {
+		m.logger.Info("Preserving permissions of the volume root directory")
+		return nil
+	}
+
+	if err := volume.SetRootPermissions(mountPath, permissions); err != nil {
+		m.logger.WithError(err).Error("Failed to set permissions for volume")
+		return err
+	}
+
+	m.logger.Infof("Set permissions of the volume root directory to mode %v, uid %v and gid %v",
+		permissions.Mode, permissions.UID, permissions.GID)
+	return nil
+}
+
 // setupDevice will return a path where the device file can be found
 // for encrypted volumes, it will try formatting the volume on first use
 // then open it and expose a crypto device at the returned path
-func (m *ShareManager) setupDevice(vol volume.Volume, devicePath string) (string, error) {
-	diskFormat, err := volume.GetDiskFormat(devicePath)
+func (m *ShareManager) setupDevice(ctx context.Context, vol volume.Volume, devicePath string) (string, error) {
+	diskFormat, err := volume.GetDiskFormat(ctx, devicePath)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to determine filesystem format of volume error")
 	}
@@ -217,26 +335,22 @@ func (m *ShareManager) setupDevice(vol volume.Volume, devicePath string) (string
 			return "", fmt.Errorf("missing passphrase for encrypted volume %v", vol.Name)
 		}
 
-		// initial setup of longhorn device for crypto
-		if diskFormat == "" {
-			options := &lhns.LuksFormatOptions{
-				KeyCipher:            vol.CryptoKeyCipher,
-				KeyHash:              vol.CryptoKeyHash,
-				KeySize:              vol.CryptoKeySize,
-				PBKDF:                vol.CryptoPBKDF,
-				PBKDFForceIterations: vol.CryptoPBKDFForceIterations,
-				PBKDFMemory:          vol.CryptoPBKDFMemory,
-			}
+		needsFormat, err := m.needsEncryption(vol, diskFormat)
+		if err != nil {
+			return "", err
+		}
 
+		// initial setup of longhorn device for crypto
+		if needsFormat {
 			m.logger.Info("Encrypting new volume before first use")
-			if err := crypto.EncryptVolume(devicePath, vol.Passphrase, options); err != nil {
+			if err := crypto.EncryptVolume(ctx, devicePath, vol.Passphrase, vol.LuksFormatOptions(), vol.CryptoIntegrity, vol.CryptoHeaderPath); err != nil {
 				return "", errors.Wrapf(err, "failed to encrypt volume %v", vol.Name)
 			}
 		}
 
 		cryptoDevice := types.GetVolumeDevicePath(vol.Name, vol.DataEngine, true)
 		m.logger.Infof("Volume %s requires crypto device %s", vol.Name, cryptoDevice)
-		if err := crypto.OpenVolume(vol.Name, vol.DataEngine, devicePath, vol.Passphrase); err != nil {
+		if err := crypto.OpenVolume(ctx, vol.Name, vol.DataEngine, devicePath, vol.Passphrase, vol.CryptoHeaderPath); err != nil {
 			m.logger.WithError(err).Error("Failed to open encrypted volume")
 			return "", err
 		}
@@ -248,14 +362,42 @@ func (m *ShareManager) setupDevice(vol volume.Volume, devicePath string) (string
 	return devicePath, nil
 }
 
-func (m *ShareManager) tearDownDevice(vol volume.Volume) error {
+// needsEncryption determines if the device has to be formatted with LUKS before first use.
+// With a detached header the ciphertext on the device has no signature and cannot be told
+// apart from an empty device, so a missing header is only created if explicitly requested.
+func (m *ShareManager) needsEncryption(vol volume.Volume, diskFormat string) (bool, error) {
+	if vol.CryptoHeaderPath == "" {
+		return diskFormat == "", nil
+	}
+
+	exists, err := crypto.DetachedHeaderExists(vol.CryptoHeaderPath)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	if !vol.CryptoHeaderInit {
+		return false, fmt.Errorf("detached LUKS header %v of volume %v does not exist, refusing to encrypt the volume without crypto header init",
+			vol.CryptoHeaderPath, vol.Name)
+	}
+	if diskFormat != "" {
+		return false, fmt.Errorf("volume %v device contains %v, refusing to create detached LUKS header %v",
+			vol.Name, diskFormat, vol.CryptoHeaderPath)
+	}
+	m.logger.Infof("Creating detached LUKS header %v", vol.CryptoHeaderPath)
+	return true, nil
+}
+
+func (m *ShareManager) tearDownDevice(ctx context.Context, vol volume.Volume) error {
 	// close any matching crypto device for this volume
 	cryptoDevice := types.GetVolumeDevicePath(vol.Name, vol.DataEngine, true)
 	if isOpen, err := crypto.IsDeviceOpen(cryptoDevice); err != nil {
 		return err
 	} else if isOpen {
 		m.logger.Infof("Volume %s has active crypto device %s", vol.Name, cryptoDevice)
-		if err := crypto.CloseVolume(vol.Name, vol.DataEngine); err != nil {
+		if err := crypto.CloseVolume(ctx, vol.Name, vol.DataEngine); err != nil {
 			return err
 		}
 		m.logger.Infof("Volume %s closed active crypto device %s", vol.Name, cryptoDevice)
@@ -264,7 +406,9 @@ func (m *ShareManager) tearDownDevice(vol volume.Volume) error {
 	return nil
 }
 
-func (m *ShareManager) MountVolume(vol volume.Volume, devicePath, mountPath string) error {
+// MountVolume mounts the device, formatting it first if it has no filesystem yet,
+// and returns whether it was formatted
+func (m *ShareManager) MountVolume(ctx context.Context, vol volume.Volume, devicePath, mountPath string) (formatted bool, err error) {
 	fsType := vol.FsType
 	mountOptions := vol.MountOptions
 	formatOptions := m.getFormatOptions()
@@ -273,10 +417,10 @@ func (m *ShareManager) MountVolume(vol volume.Volume, devicePath, mountPath stri
 	// pre v1.2 we ignored the fsType and always formatted as ext4
 	// after v1.2 we include the user specified fsType to be able to
 	// mount priorly created volumes we need to switch to the existing fsType
-	diskFormat, err := volume.GetDiskFormat(devicePath)
+	diskFormat, err := volume.GetDiskFormat(ctx, devicePath)
 	if err != nil {
 		m.logger.WithError(err).Error("Failed to evaluate disk format")
-		return err
+		return false, err
 	}
 
 	// `unknown data, probably partitions` is used when the disk contains a partition table
@@ -285,7 +429,21 @@ func (m *ShareManager) MountVolume(vol volume.Volume, devicePath, mountPath stri
 		fsType = diskFormat
 	}
 
-	return volume.MountVolume(devicePath, mountPath, fsType, mountOptions, formatOptions)
+	if m.enableProjectQuota {
+		mountOptions, formatOptions = m.projectQuotaOptions(ctx, devicePath, mountPath, fsType, diskFormat, mountOptions, formatOptions)
+	}
+
+	if fsType == volume.FsTypeBtrfs && vol.BtrfsSubvolume != "" {
+		if mountOptions, err = m.btrfsSubvolumeOptions(ctx, vol.BtrfsSubvolume, devicePath, mountPath, mountOptions, formatOptions); err != nil {
+			return diskFormat == "", err
+		}
+	}
+
+	// formats the device first if it has no filesystem yet
+	if err = volume.MountVolume(ctx, devicePath, mountPath, fsType, mountOptions, formatOptions); err != nil {
+		return diskFormat == "", err
+	}
+	return diskFormat == "", m.mountStateDirectory(ctx, mountPath)
 }
 
 func (m *ShareManager) getFormatOptions() []string {
@@ -297,9 +455,10 @@ func (m *ShareManager) getFormatOptions() []string {
 	return strings.Split(env, ":")
 }
 
-func (m *ShareManager) resizeVolume(devicePath, mountPath string) error {
+func (m *ShareManager) resizeVolume(ctx context.Context, devicePath, mountPath string) error {
 	// Note that we don't need 'cryptsetup resize' here.  The crypto 'open' will have done so if necessary.
-	if resized, err := volume.ResizeVolume(devicePath, mountPath); err != nil {
+	resized, err := volume.ResizeVolume(ctx, devicePath, mountPath)
+	if err != nil {
 		m.logger.WithError(err).Error("Failed to resize filesystem for volume")
 		return err
 	} else if resized {
@@ -351,11 +510,24 @@ func (m *ShareManager) NewKubeClient() (*kubernetes.Clientset, error) {
 	return clientset, nil
 }
 
-func (m *ShareManager) takeLease() error {
+func (m *ShareManager) takeLease() (err error) {
 	if m.leaseClient == nil {
 		return fmt.Errorf("kubernetes API client is unset")
 	}
 
+	start := time.Now()
+	previousHolder := ""
+	defer func() {
+		m.auditLogger.LogResult(audit.Entry{
+			Event:  audit.EventLeaseTakeover,
+			Volume: m.volume.Name,
+			Details: map[string]string{
+				"previousHolder": previousHolder,
+				"holder":         m.leaseHolder,
+			},
+		}, start, err)
+	}()
+
 	lease, err := m.leaseClient.Leases(m.namespace).Get(m.context, m.volume.Name, metav1.GetOptions{})
 	if err != nil {
 		return err
@@ -364,6 +536,7 @@ func (m *ShareManager) takeLease() error {
 
 	now := time.Now()
 	currentHolder := *m.lease.Spec.HolderIdentity
+	previousHolder = currentHolder
 	m.logger.Infof("Updating lease holderIdentity from %v to %v", currentHolder, m.leaseHolder)
 
 	*m.lease.Spec.HolderIdentity = m.leaseHolder
@@ -422,17 +595,29 @@ func (m *ShareManager) runHealthCheck() {
 			if err := m.hasHealthyVolume(); err != nil {
 				if strings.Contains(err.Error(), "UNHEALTHY") {
 					m.logger.WithError(err).Error("Terminating")
-					m.Shutdown()
+					m.Shutdown(err.Error())
 					return
 				} else if strings.Contains(err.Error(), "READONLY") {
 					m.logger.WithError(err).Warn("Recovering read only volume")
-					if err := m.recoverReadOnlyVolume(); err != nil {
+					start := time.Now()
+					err := m.recoverReadOnlyVolume()
+					m.auditLogger.LogResult(audit.Entry{
+						Event:  audit.EventReadOnlyRecovery,
+						Volume: m.volume.Name,
+					}, start, err)
+					if err != nil {
 						m.logger.WithError(err).Error("Volume is unable to recover by remounting, terminating")
-						m.Shutdown()
+						m.Shutdown("unable to recover read only volume: " + err.Error())
 						return
 					}
 				}
 			}
+
+			// Integrity errors are reported but don't terminate the server, the corruption
+			// is on the replicas and would follow the volume to the next share manager.
+			if err := m.checkIntegrity(); err != nil {
+				m.logger.WithError(err).Error("Detected integrity errors on volume")
+			}
 		}
 	}
 }
@@ -452,14 +637,54 @@ func (m *ShareManager) hasHealthyVolume() error {
 		return fmt.Errorf(UnhealthyErr, mountPath)
 	}
 
-	if stat.Flags&unix.ST_RDONLY != 0 {
-		logrus.Errorf("Mount path %v is marked read-only at the kernel level", mountPath)
-		return fmt.Errorf(ReadOnlyErr, mountPath)
+	return checkWritable(mountPath, stat)
+}
+
+// checkWritable returns an error if the filesystem has been marked read-only at the kernel level
+func checkWritable(mountPath string, stat unix.Statfs_t) error {
+	if stat.Flags&unix.ST_RDONLY == 0 {
+		return nil
+	}
+
+	// btrfs turns read-only when it aborts a transaction, it refuses a remount read-write
+	// until the filesystem is unmounted, so the volume has to be mounted again from scratch
+	if stat.Type == unix.BTRFS_SUPER_MAGIC {
+		logrus.Errorf("Mount path %v is a btrfs filesystem which turned read-only, it cannot be remounted", mountPath)
+		return fmt.Errorf(UnhealthyErr, mountPath)
+	}
+	logrus.Errorf("Mount path %v is marked read-only at the kernel level", mountPath)
+	return fmt.Errorf(ReadOnlyErr, mountPath)
+}
+
+// checkIntegrity returns an error if the kernel detected new integrity mismatches
+// on the dm-integrity device since the last check.
+func (m *ShareManager) checkIntegrity() error {
+	if !m.HasIntegrityProtection() {
+		return nil
+	}
+
+	mismatches, err := crypto.GetIntegrityMismatches(m.volume.Name, m.volume.DataEngine)
+	if err != nil {
+		m.logger.WithError(err).Warn("Failed to get integrity mismatches of volume")
+		return nil
 	}
 
+	if previous := m.integrityMismatches.Swap(mismatches); mismatches > previous {
+		return fmt.Errorf(IntegrityErr, types.GetMountPath(m.volume.Name), mismatches)
+	}
 	return nil
 }
 
+// HasIntegrityErrors returns true if the kernel detected integrity mismatches on the volume.
+func (m *ShareManager) HasIntegrityErrors() bool {
+	return m.integrityMismatches.Load() > 0
+}
+
+// HasIntegrityProtection returns true if the volume uses LUKS2 authenticated encryption.
+func (m *ShareManager) HasIntegrityProtection() bool {
+	return m.volume.IsEncrypted() && crypto.HasIntegrityDevice(m.volume.Name, m.volume.DataEngine)
+}
+
 func (m *ShareManager) recoverReadOnlyVolume() error {
 	mountPath := types.GetMountPath(m.volume.Name)
 
@@ -483,6 +708,27 @@ func (m *ShareManager) ShareIsExported() bool {
 	return m.shareExported.Load()
 }
 
+// ExportVolume adds the export of the volume to the config, without reloading the nfs server
+func (m *ShareManager) ExportVolume(volume string) error {
+	_, err := m.nfsServer.CreateExport(volume)
+	return err
+}
+
+// UnexportVolume removes the export of the volume from the config, without reloading the nfs server
+func (m *ShareManager) UnexportVolume(volume string) error {
+	return m.nfsServer.DeleteExport(volume)
+}
+
+// ReloadExport makes the nfs server reread the export configuration
+func (m *ShareManager) ReloadExport(ctx context.Context) error {
+	return m.nfsServer.ReloadExport(ctx)
+}
+
+// GetNFSPorts returns the ports the nfs server listens on
+func (m *ShareManager) GetNFSPorts() []nfs.Port {
+	return m.nfsServer.Ports()
+}
+
 func (m *ShareManager) IsServing() bool {
 	if !m.ShareIsExported() {
 		return false
@@ -491,6 +737,19 @@ func (m *ShareManager) IsServing() bool {
 	return m.hasHealthyVolume() == nil
 }
 
-func (m *ShareManager) Shutdown() {
+// Shutdown stops the share manager, the reason of the first call is recorded in the audit log.
+func (m *ShareManager) Shutdown(reason string) {
+	m.shutdownOnce.Do(func() {
+		m.logger.Infof("Shutting down share manager: %v", reason)
+		now := time.Now()
+		m.auditLogger.Log(audit.Entry{
+			Event:   audit.EventShutdown,
+			Volume:  m.volume.Name,
+			Start:   now,
+			End:     now,
+			Outcome: audit.OutcomeSuccess,
+			Details: map[string]string{"reason": reason},
+		})
+	})
 	m.shutdown()
 }
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/volume"
+)
+
+func TestSetRootPermissionsPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    string
+		formatted bool
+		wantSet   bool
+	}{
+		{name: "always on an existing filesystem", policy: volume.RootPermissionsAlways, wantSet: true},
+		{name: "always on a new filesystem", policy: volume.RootPermissionsAlways, formatted: true, wantSet: true},
+		{name: "preserve on an existing filesystem", policy: volume.RootPermissionsPreserve},
+		{name: "preserve on a new filesystem", policy: volume.RootPermissionsPreserve, formatted: true, wantSet: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &ShareManager{logger: logrus.New()}
+			vol := volume.Volume{Name: "vol", RootPermissions: volume.RootPermissions{Mode: 0777, UID: -1, GID: -1, Policy: tt.policy}}
+			// the directory is not a mount point, so setting the permissions fails instead of
+			// touching it, which tells whether they were set
+			mountPath := t.TempDir()
+			if err := os.Chmod(mountPath, 0700); err != nil {
+				t.Fatal(err)
+			}
+
+			err := m.setRootPermissions(vol, mountPath, tt.formatted)
+			if set := err != nil; set != tt.wantSet {
+				t.Errorf("setRootPermissions() error = %v, want the permissions set %v", err, tt.wantSet)
+			}
+			info, err := os.Stat(mountPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0700 {
+				t.Errorf("setRootPermissions() changed the mode of a directory which is not a mount point to %v", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	const mountPath = "/export/vol"
+
+	tests := []struct {
+		name    string
+		stat    unix.Statfs_t
+		wantErr string
+	}{
+		{name: "writable ext4", stat: unix.Statfs_t{Type: unix.EXT4_SUPER_MAGIC}},
+		{name: "writable btrfs", stat: unix.Statfs_t{Type: unix.BTRFS_SUPER_MAGIC}},
+		{name: "read-only ext4 can be remounted", stat: unix.Statfs_t{Type: unix.EXT4_SUPER_MAGIC, Flags: unix.ST_RDONLY}, wantErr: fmt.Sprintf(ReadOnlyErr, mountPath)},
+		{name: "read-only btrfs has to be mounted again", stat: unix.Statfs_t{Type: unix.BTRFS_SUPER_MAGIC, Flags: unix.ST_RDONLY}, wantErr: fmt.Sprintf(UnhealthyErr, mountPath)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkWritable(mountPath, tt.stat)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkWritable() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("checkWritable() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBtrfsSubvolumeOptions(t *testing.T) {
+	// the subvolume is only created while the mount path is not mounted yet
+	const mountPath = "/"
+	if !volume.CheckMountValid(mountPath) {
+		t.Skipf("%v is not a mount point", mountPath)
+	}
+
+	m := &ShareManager{logger: logrus.New()}
+	mountOptions := make([]string, 1, 2)
+	mountOptions[0] = "noatime"
+
+	got, err := m.btrfsSubvolumeOptions(context.Background(), "data/vol", "/dev/null", mountPath, mountOptions, nil)
+	if err != nil {
+		t.Fatalf("btrfsSubvolumeOptions() error = %v", err)
+	}
+	if want := []string{"noatime", "subvol=data/vol"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("btrfsSubvolumeOptions() = %v, want %v", got, want)
+	}
+	if mountOptions[:2][1] != "" {
+		t.Errorf("btrfsSubvolumeOptions() changed the configured mount options to %v", mountOptions[:2])
+	}
+}
@@ -0,0 +1,386 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sys/unix"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/crypto"
+	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+	"github.com/longhorn/longhorn-share-manager/pkg/util"
+	"github.com/longhorn/longhorn-share-manager/pkg/volume"
+)
+
+var (
+	ErrInvalidSnapshot     = errors.New("invalid snapshot")
+	ErrSnapshotExported    = errors.New("snapshot is already exported")
+	ErrSnapshotNotExported = errors.New("snapshot is not exported")
+)
+
+// SnapshotExport is a snapshot or backup of the volume, attached as an additional block device,
+// which is mounted read-only below the volume and exported next to it
+type SnapshotExport struct {
+	Name       string
+	DevicePath string
+	FsType     string
+	// Encrypted is set if the device was opened with the passphrase of the volume
+	Encrypted bool
+	nfs.ExportOptions
+}
+
+// ListSnapshotExports returns the exported snapshots. They are not persisted, the device of a
+// snapshot is only attached to the node of this share manager.
+func (m *ShareManager) ListSnapshotExports() []SnapshotExport {
+	m.snapshotMutex.Lock()
+	defer m.snapshotMutex.Unlock()
+
+	snapshots := make([]SnapshotExport, 0, len(m.snapshots))
+	for _, snapshot := range m.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	slices.SortFunc(snapshots, func(a, b SnapshotExport) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return snapshots
+}
+
+// GetSnapshotExportID returns the export id of the snapshot, 0 if it is not exported
+func (m *ShareManager) GetSnapshotExportID(name string) uint16 {
+	return m.nfsServer.GetSnapshotExport(m.volume.Name, name)
+}
+
+// ExportSnapshot mounts the device of a snapshot read-only and exports it at
+// /<volume>/.snapshots/<name>. A device with a LUKS signature is opened with the passphrase of
+// the volume first. It returns the snapshot with the defaults applied.
+func (m *ShareManager) ExportSnapshot(ctx context.Context, snapshot SnapshotExport) (_ SnapshotExport, err error) {
+	ctx, span := tracing.Start(ctx, "ShareManager.ExportSnapshot",
+		attribute.String("name", snapshot.Name), attribute.String("devicePath", snapshot.DevicePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	m.snapshotMutex.Lock()
+	defer m.snapshotMutex.Unlock()
+
+	if err := m.validateSnapshot(snapshot); err != nil {
+		return SnapshotExport{}, err
+	}
+	snapshot.AccessType = "RO"
+	if snapshot.ExportOptions, err = snapshot.ExportOptions.Normalize(); err != nil {
+		return SnapshotExport{}, errors.Wrap(ErrInvalidSnapshot, err.Error())
+	}
+
+	devicePath, err := m.openSnapshotDevice(ctx, &snapshot)
+	if err != nil {
+		return SnapshotExport{}, err
+	}
+	defer func() {
+		if err != nil && snapshot.Encrypted {
+			if errClose := crypto.CloseVolume(ctx, types.GetSnapshotDeviceName(m.volume.Name, snapshot.Name), types.DataEngineTypeV1); errClose != nil {
+				m.logger.WithError(errClose).Warnf("Failed to close crypto device of snapshot %v", snapshot.Name)
+			}
+		}
+	}()
+
+	if snapshot.FsType, err = volume.GetDiskFormat(ctx, devicePath); err != nil {
+		return SnapshotExport{}, err
+	}
+	if snapshot.FsType == "" || strings.Contains(snapshot.FsType, "unknown data") {
+		return SnapshotExport{}, errors.Wrapf(ErrInvalidSnapshot, "device %v of snapshot %v has no filesystem", devicePath, snapshot.Name)
+	}
+
+	if err := m.mountSnapshot(ctx, snapshot, devicePath); err != nil {
+		return SnapshotExport{}, err
+	}
+
+	exportID, err := m.claimExportID(snapshotExportIDKey(snapshot.Name))
+	if err != nil {
+		m.unmountSnapshot(ctx, snapshot.Name)
+		return SnapshotExport{}, err
+	}
+	if _, err := m.nfsServer.CreateSnapshotExport(m.volume.Name, snapshot.Name, exportID, snapshot.ExportOptions); err != nil {
+		m.unmountSnapshot(ctx, snapshot.Name)
+		return SnapshotExport{}, err
+	}
+	m.snapshots[snapshot.Name] = snapshot
+
+	m.logger.Infof("Exported %v snapshot %v of device %v at %v", snapshot.FsType, snapshot.Name, snapshot.DevicePath,
+		nfs.SnapshotPseudoPath(m.volume.Name, snapshot.Name))
+	return snapshot, m.nfsServer.ReloadExport(ctx)
+}
+
+// UnexportSnapshot removes the export of the snapshot, unmounts it and closes its crypto device.
+// The device itself stays attached.
+func (m *ShareManager) UnexportSnapshot(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "ShareManager.UnexportSnapshot", attribute.String("name", name))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	m.snapshotMutex.Lock()
+	defer m.snapshotMutex.Unlock()
+
+	if _, ok := m.snapshots[name]; !ok {
+		return errors.Wrapf(ErrSnapshotNotExported, "snapshot %v", name)
+	}
+
+	if err := m.nfsServer.DeleteSnapshotExport(m.volume.Name, name); err != nil {
+		return err
+	}
+	if err := m.nfsServer.ReloadExport(ctx); err != nil {
+		return err
+	}
+	if err := m.releaseSnapshot(ctx, name); err != nil {
+		return err
+	}
+
+	m.logger.Infof("Unexported snapshot %v", name)
+	return nil
+}
+
+// ReleaseSnapshots unmounts all snapshots and closes their crypto devices, so the volume can be
+// unmounted. Their exports have to be removed before.
+func (m *ShareManager) ReleaseSnapshots(ctx context.Context) error {
+	m.snapshotMutex.Lock()
+	defer m.snapshotMutex.Unlock()
+
+	var errs error
+	for name := range m.snapshots {
+		errs = errors.CombineErrors(errs, m.releaseSnapshot(ctx, name))
+	}
+	return errs
+}
+
+// exportSnapshots adds the exported snapshots to the config, after the exports were removed
+// with the sub-exports
+func (m *ShareManager) exportSnapshots() error {
+	m.snapshotMutex.Lock()
+	defer m.snapshotMutex.Unlock()
+
+	var errs error
+	for _, snapshot := range m.snapshots {
+		exportID, err := m.claimExportID(snapshotExportIDKey(snapshot.Name))
+		if err != nil {
+			errs = errors.CombineErrors(errs, err)
+			continue
+		}
+		if _, err := m.nfsServer.CreateSnapshotExport(m.volume.Name, snapshot.Name, exportID, snapshot.ExportOptions); err != nil {
+			errs = errors.CombineErrors(errs, err)
+		}
+	}
+	return errs
+}
+
+func (m *ShareManager) validateSnapshot(snapshot SnapshotExport) error {
+	if !subExportNameRegex.MatchString(snapshot.Name) {
+		return errors.Wrapf(ErrInvalidSnapshot, "name %q must consist of up to 63 letters, digits, '.', '_' or '-'", snapshot.Name)
+	}
+	if _, ok := m.snapshots[snapshot.Name]; ok {
+		return errors.Wrapf(ErrSnapshotExported, "snapshot %v", snapshot.Name)
+	}
+	// the LUKS header of the volume lives outside of the device, a snapshot of the device has none
+	if m.volume.CryptoHeaderPath != "" {
+		return errors.Wrapf(ErrInvalidSnapshot, "snapshots of volume %v with a detached LUKS header cannot be exported", m.volume.Name)
+	}
+
+	if !filepath.IsAbs(snapshot.DevicePath) || !volume.CheckDeviceValid(snapshot.DevicePath) {
+		return errors.Wrapf(ErrInvalidSnapshot, "device %q is not a block device", snapshot.DevicePath)
+	}
+	deviceNumber, err := util.GetDeviceNumber(snapshot.DevicePath)
+	if err != nil {
+		return err
+	}
+	devicePaths := []string{types.GetRawVolumeDevicePath(m.volume.Name), types.GetVolumeDevicePath(m.volume.Name, m.volume.DataEngine, true)}
+	for _, other := range m.snapshots {
+		devicePaths = append(devicePaths, other.DevicePath)
+	}
+	for _, devicePath := range devicePaths {
+		if otherNumber, err := util.GetDeviceNumber(devicePath); err == nil && otherNumber == deviceNumber {
+			return errors.Wrapf(ErrInvalidSnapshot, "device %v is already used by the volume or another snapshot", snapshot.DevicePath)
+		}
+	}
+	return nil
+}
+
+// openSnapshotDevice returns the device to mount, a device with a LUKS signature is opened first
+func (m *ShareManager) openSnapshotDevice(ctx context.Context, snapshot *SnapshotExport) (string, error) {
+	diskFormat, err := volume.GetDiskFormat(ctx, snapshot.DevicePath)
+	if err != nil {
+		return "", err
+	}
+	if diskFormat != "crypto_LUKS" {
+		return snapshot.DevicePath, nil
+	}
+	if m.volume.Passphrase == "" {
+		return "", errors.Wrapf(ErrInvalidSnapshot, "device %v of snapshot %v is encrypted, but the volume has no passphrase", snapshot.DevicePath, snapshot.Name)
+	}
+
+	deviceName := types.GetSnapshotDeviceName(m.volume.Name, snapshot.Name)
+	if err := crypto.OpenVolumeReadOnly(ctx, deviceName, types.DataEngineTypeV1, snapshot.DevicePath, m.volume.Passphrase); err != nil {
+		return "", errors.Wrapf(err, "failed to open encrypted device %v of snapshot %v", snapshot.DevicePath, snapshot.Name)
+	}
+	snapshot.Encrypted = true
+	return types.GetVolumeDevicePath(deviceName, types.DataEngineTypeV1, true), nil
+}
+
+func (m *ShareManager) mountSnapshot(ctx context.Context, snapshot SnapshotExport, devicePath string) error {
+	mountPoint, err := openSnapshotMountPoint(types.GetMountPath(m.volume.Name), snapshot.Name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = mountPoint.Close()
+	}()
+
+	if err := volume.MountReadOnly(ctx, devicePath, mountPoint, snapshot.FsType); err != nil {
+		return errors.Wrapf(err, "failed to mount snapshot %v read-only", snapshot.Name)
+	}
+	return nil
+}
+
+// openSnapshotMountPoint creates and opens the directory of the snapshot below the mount path.
+// Clients can change the directories of the volume, so every component is opened relative to
+// its parent without following symlinks, and the snapshot is mounted on the opened directory.
+func openSnapshotMountPoint(mountPath, name string) (*os.File, error) {
+	root, err := unix.Open(mountPath, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %v", mountPath)
+	}
+	defer func() {
+		_ = unix.Close(root)
+	}()
+
+	snapshotsDir, err := openDirectoryAt(root, types.SnapshotsDirName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = unix.Close(snapshotsDir)
+	}()
+
+	dir, err := openDirectoryAt(snapshotsDir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// a directory on another filesystem is a mount point already
+	var parentStat, dirStat unix.Stat_t
+	if err := unix.Fstat(snapshotsDir, &parentStat); err != nil {
+		_ = unix.Close(dir)
+		return nil, err
+	}
+	if err := unix.Fstat(dir, &dirStat); err != nil {
+		_ = unix.Close(dir)
+		return nil, err
+	}
+	if parentStat.Dev != dirStat.Dev {
+		_ = unix.Close(dir)
+		return nil, errors.Wrapf(ErrInvalidSnapshot, "mount point of snapshot %v is in use", name)
+	}
+
+	return os.NewFile(uintptr(dir), filepath.Join(mountPath, types.SnapshotsDirName, name)), nil
+}
+
+// openDirectoryAt creates the directory below the parent if missing and opens it, a symlink
+// in its place is refused
+func openDirectoryAt(parent int, name string) (int, error) {
+	if err := unix.Mkdirat(parent, name, 0755); err != nil && !errors.Is(err, unix.EEXIST) {
+		return -1, errors.Wrapf(err, "failed to create directory %v", name)
+	}
+	fd, err := unix.Openat(parent, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.ELOOP) || errors.Is(err, unix.ENOTDIR) {
+			return -1, errors.Wrapf(ErrInvalidPath, "%v is not a directory", name)
+		}
+		return -1, errors.Wrapf(err, "failed to open directory %v", name)
+	}
+	return fd, nil
+}
+
+func (m *ShareManager) unmountSnapshot(ctx context.Context, name string) {
+	if err := removeSnapshotMountPoint(ctx, types.GetMountPath(m.volume.Name), name); err != nil {
+		m.logger.WithError(err).Warnf("Failed to unmount snapshot %v", name)
+	}
+}
+
+// removeSnapshotMountPoint unmounts the snapshot and removes its directory. Like on mount, the
+// snapshots directory is opened without following symlinks, and the snapshot is unmounted and
+// its directory removed relative to it, so a symlink swapped in by a client is not followed.
+// A missing or unmounted directory is not an error.
+func removeSnapshotMountPoint(ctx context.Context, mountPath, name string) (err error) {
+	_, span := tracing.Start(ctx, "removeSnapshotMountPoint", attribute.String("name", name))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	root, err := unix.Open(mountPath, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %v", mountPath)
+	}
+	defer func() {
+		_ = unix.Close(root)
+	}()
+
+	snapshotsDir, err := unix.Openat(root, types.SnapshotsDirName, unix.O_PATH|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		switch {
+		case errors.Is(err, unix.ENOENT):
+			return nil
+		case errors.Is(err, unix.ELOOP) || errors.Is(err, unix.ENOTDIR):
+			return errors.Wrapf(ErrInvalidPath, "%v is not a directory", types.SnapshotsDirName)
+		}
+		return errors.Wrapf(err, "failed to open directory %v", types.SnapshotsDirName)
+	}
+	defer func() {
+		_ = unix.Close(snapshotsDir)
+	}()
+
+	// the link of the descriptor resolves to the opened directory, and the snapshot directory
+	// itself is not followed
+	target := fmt.Sprintf("/proc/self/fd/%d/%s", snapshotsDir, name)
+	if err := unix.Unmount(target, unix.UMOUNT_NOFOLLOW); err != nil && !errors.Is(err, unix.EINVAL) && !errors.Is(err, unix.ENOENT) {
+		return errors.Wrapf(err, "failed to unmount snapshot %v", name)
+	}
+
+	if err := unix.Unlinkat(snapshotsDir, name, unix.AT_REMOVEDIR); err != nil {
+		switch {
+		case errors.Is(err, unix.ENOENT):
+			return nil
+		case errors.Is(err, unix.ENOTDIR):
+			return errors.Wrapf(ErrInvalidPath, "mount point of snapshot %v is not a directory", name)
+		}
+		return errors.Wrapf(err, "failed to remove mount point of snapshot %v", name)
+	}
+	return nil
+}
+
+// releaseSnapshot unmounts the snapshot, closes its crypto device and forgets it
+func (m *ShareManager) releaseSnapshot(ctx context.Context, name string) error {
+	snapshot := m.snapshots[name]
+
+	if err := removeSnapshotMountPoint(ctx, types.GetMountPath(m.volume.Name), name); err != nil {
+		return err
+	}
+
+	if snapshot.Encrypted {
+		if err := crypto.CloseVolume(ctx, types.GetSnapshotDeviceName(m.volume.Name, name), types.DataEngineTypeV1); err != nil {
+			return errors.Wrapf(err, "failed to close crypto device of snapshot %v", name)
+		}
+	}
+
+	if err := m.releaseExportID(snapshotExportIDKey(name)); err != nil {
+		m.logger.WithError(err).Warnf("Failed to release export id of snapshot %v", name)
+	}
+
+	delete(m.snapshots, name)
+	return nil
+}
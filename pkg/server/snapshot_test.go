@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+	"github.com/longhorn/longhorn-share-manager/pkg/volume"
+)
+
+func TestOpenSnapshotMountPoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, mountPath, outside string)
+		wantErr error
+	}{
+		{name: "missing directories are created"},
+		{
+			name: "existing directory",
+			setup: func(t *testing.T, mountPath, _ string) {
+				mkdirAll(t, filepath.Join(mountPath, types.SnapshotsDirName, "snap"))
+			},
+		},
+		{
+			name: "snapshots directory is a symlink",
+			setup: func(t *testing.T, mountPath, outside string) {
+				symlink(t, outside, filepath.Join(mountPath, types.SnapshotsDirName))
+			},
+			wantErr: ErrInvalidPath,
+		},
+		{
+			name: "snapshot directory is a symlink",
+			setup: func(t *testing.T, mountPath, outside string) {
+				mkdirAll(t, filepath.Join(mountPath, types.SnapshotsDirName))
+				symlink(t, outside, filepath.Join(mountPath, types.SnapshotsDirName, "snap"))
+			},
+			wantErr: ErrInvalidPath,
+		},
+		{
+			name: "snapshot directory is a file",
+			setup: func(t *testing.T, mountPath, _ string) {
+				mkdirAll(t, filepath.Join(mountPath, types.SnapshotsDirName))
+				if err := os.WriteFile(filepath.Join(mountPath, types.SnapshotsDirName, "snap"), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: ErrInvalidPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			mountPath := filepath.Join(root, "vol")
+			outside := filepath.Join(root, "outside")
+			mkdirAll(t, mountPath)
+			mkdirAll(t, outside)
+			if tt.setup != nil {
+				tt.setup(t, mountPath, outside)
+			}
+
+			dir, err := openSnapshotMountPoint(mountPath, "snap")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("openSnapshotMountPoint() error = %v, want %v", err, tt.wantErr)
+				}
+				entries, _ := os.ReadDir(outside)
+				if len(entries) != 0 {
+					t.Errorf("openSnapshotMountPoint() created %v outside of the volume", entries[0].Name())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("openSnapshotMountPoint() error = %v", err)
+			}
+			defer dir.Close()
+
+			want, err := os.Stat(filepath.Join(mountPath, types.SnapshotsDirName, "snap"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := dir.Stat()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !os.SameFile(got, want) {
+				t.Errorf("openSnapshotMountPoint() opened %v, want the snapshot directory", dir.Name())
+			}
+		})
+	}
+}
+
+func TestRemoveSnapshotMountPoint(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("unmounting requires root")
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, mountPath, outside string)
+		wantErr error
+	}{
+		{name: "missing snapshots directory"},
+		{
+			name: "missing snapshot directory",
+			setup: func(t *testing.T, mountPath, _ string) {
+				mkdirAll(t, filepath.Join(mountPath, types.SnapshotsDirName))
+			},
+		},
+		{
+			name: "unmounted directory",
+			setup: func(t *testing.T, mountPath, _ string) {
+				mkdirAll(t, filepath.Join(mountPath, types.SnapshotsDirName, "snap"))
+			},
+		},
+		{
+			name: "mounted directory",
+			setup: func(t *testing.T, mountPath, _ string) {
+				dir := filepath.Join(mountPath, types.SnapshotsDirName, "snap")
+				mkdirAll(t, dir)
+				if err := unix.Mount("tmpfs", dir, "tmpfs", unix.MS_RDONLY, ""); err != nil {
+					t.Skipf("cannot mount tmpfs: %v", err)
+				}
+				t.Cleanup(func() {
+					_ = unix.Unmount(dir, unix.MNT_DETACH)
+				})
+			},
+		},
+		{
+			name: "snapshots directory is a symlink",
+			setup: func(t *testing.T, mountPath, outside string) {
+				symlink(t, outside, filepath.Join(mountPath, types.SnapshotsDirName))
+			},
+			wantErr: ErrInvalidPath,
+		},
+		{
+			name: "snapshot directory is a symlink",
+			setup: func(t *testing.T, mountPath, outside string) {
+				mkdirAll(t, filepath.Join(mountPath, types.SnapshotsDirName))
+				symlink(t, filepath.Join(outside, "snap"), filepath.Join(mountPath, types.SnapshotsDirName, "snap"))
+			},
+			wantErr: ErrInvalidPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			mountPath := filepath.Join(root, "vol")
+			outside := filepath.Join(root, "outside")
+			mkdirAll(t, mountPath)
+			mkdirAll(t, filepath.Join(outside, "snap"))
+			if tt.setup != nil {
+				tt.setup(t, mountPath, outside)
+			}
+
+			err := removeSnapshotMountPoint(context.Background(), mountPath, "snap")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("removeSnapshotMountPoint() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(outside, "snap")); err != nil {
+				t.Errorf("removeSnapshotMountPoint() touched the directory outside of the volume: %v", err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if _, err := os.Lstat(filepath.Join(mountPath, types.SnapshotsDirName, "snap")); !os.IsNotExist(err) {
+				t.Errorf("removeSnapshotMountPoint() left the mount point behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSnapshotDetachedHeader(t *testing.T) {
+	m := &ShareManager{
+		volume:    volume.Volume{Name: "vol", CryptoHeaderPath: "/var/lib/longhorn/headers/vol"},
+		snapshots: map[string]SnapshotExport{},
+	}
+	err := m.validateSnapshot(SnapshotExport{Name: "snap", DevicePath: "/dev/null"})
+	if !errors.Is(err, ErrInvalidSnapshot) {
+		t.Errorf("validateSnapshot() error = %v, want %v", err, ErrInvalidSnapshot)
+	}
+}
+
+func mkdirAll(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func symlink(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+}
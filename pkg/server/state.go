@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+	"github.com/longhorn/longhorn-share-manager/pkg/volume"
+)
+
+// mountStateDirectory mounts the volume a second time out of the export, where the share manager
+// keeps its state, and hides the state directory in the export, so clients cannot change the
+// sub-exports, export ids or recovery records.
+func (m *ShareManager) mountStateDirectory(ctx context.Context, mountPath string) error {
+	stateMountPath := types.GetStateMountPath(m.volume.Name)
+	if err := volume.BindMountPrivate(ctx, mountPath, stateMountPath); err != nil {
+		return errors.Wrapf(err, "failed to mount volume at %v", stateMountPath)
+	}
+
+	// a client may have replaced the state directory while it was exported without hiding it
+	statePath := types.GetStatePath(m.volume.Name)
+	if info, err := os.Lstat(statePath); err == nil && !info.IsDir() {
+		m.logger.Warnf("Replacing %v which is not a directory", statePath)
+		if err := os.Remove(statePath); err != nil {
+			return errors.Wrapf(err, "failed to remove %v", statePath)
+		}
+	}
+	if err := os.MkdirAll(statePath, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create state directory %v", statePath)
+	}
+	if err := os.Chmod(statePath, 0700); err != nil {
+		return errors.Wrapf(err, "failed to set permissions of state directory %v", statePath)
+	}
+
+	if err := volume.HideDirectory(ctx, filepath.Join(mountPath, types.StateDirName)); err != nil {
+		return errors.Wrap(err, "failed to hide state directory from the clients")
+	}
+	return nil
+}
+
+// UnmountVolume unmounts the volume together with the mounts of its state directory
+func (m *ShareManager) UnmountVolume(ctx context.Context, mountPath string) error {
+	for _, path := range []string{filepath.Join(mountPath, types.StateDirName), types.GetStateMountPath(m.volume.Name), mountPath} {
+		if !volume.CheckMountValid(path) {
+			continue
+		}
+		if err := volume.UnmountVolume(ctx, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
+	"github.com/longhorn/longhorn-share-manager/pkg/types"
+	"github.com/longhorn/longhorn-share-manager/pkg/util"
+)
+
+const subExportsFile = "sub-exports.json"
+
+var (
+	ErrInvalidSubExport  = errors.New("invalid sub-export")
+	ErrSubExportExists   = errors.New("sub-export already exists")
+	ErrSubExportNotFound = errors.New("sub-export not found")
+
+	subExportNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+)
+
+// ListSubExports returns the sub-exports persisted on the volume
+func (m *ShareManager) ListSubExports() ([]nfs.SubExport, error) {
+	return loadSubExports(m.volume.Name)
+}
+
+// GetSubExportID returns the export id of the sub-export, 0 if it is not exported
+func (m *ShareManager) GetSubExportID(name string) uint16 {
+	return m.nfsServer.GetSubExport(m.volume.Name, name)
+}
+
+// CreateSubExport exports a directory of the mounted volume, which is created if missing,
+// and persists the sub-export on the volume. It returns the sub-export with the defaults applied.
+func (m *ShareManager) CreateSubExport(ctx context.Context, subExport nfs.SubExport) (_ nfs.SubExport, err error) {
+	ctx, span := tracing.Start(ctx, "ShareManager.CreateSubExport", attribute.String("name", subExport.Name))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	subExports, err := loadSubExports(m.volume.Name)
+	if err != nil {
+		return nfs.SubExport{}, err
+	}
+	if slices.ContainsFunc(subExports, func(existing nfs.SubExport) bool { return existing.Name == subExport.Name }) {
+		return nfs.SubExport{}, errors.Wrapf(ErrSubExportExists, "sub-export %v", subExport.Name)
+	}
+
+	subExport, err = m.normalizeSubExport(subExport, subExports)
+	if err != nil {
+		return nfs.SubExport{}, err
+	}
+
+	// check the existing part of the path before creating the missing directories
+	if err := checkPathInVolume(m.volume.Name, subExport.Path); err != nil {
+		return nfs.SubExport{}, err
+	}
+	exportPath := filepath.Join(types.GetMountPath(m.volume.Name), subExport.Path)
+	if err := os.MkdirAll(exportPath, 0777); err != nil {
+		return nfs.SubExport{}, errors.Wrapf(err, "failed to create directory %v", exportPath)
+	}
+	if err := checkDirectoryInVolume(m.volume.Name, subExport.Path); err != nil {
+		return nfs.SubExport{}, err
+	}
+
+	exportID, err := m.claimExportID(subExportIDKey(subExport.Name))
+	if err != nil {
+		return nfs.SubExport{}, err
+	}
+	if _, err := m.nfsServer.CreateSubExport(m.volume.Name, subExport, exportID); err != nil {
+		return nfs.SubExport{}, err
+	}
+	if err := saveSubExports(m.volume.Name, append(subExports, subExport)); err != nil {
+		if errDelete := m.nfsServer.DeleteSubExport(m.volume.Name, subExport.Name); errDelete != nil {
+			m.logger.WithError(errDelete).Warnf("Failed to delete export of sub-export %v", subExport.Name)
+		}
+		return nfs.SubExport{}, err
+	}
+
+	m.logger.Infof("Created sub-export %v of path %v at %v", subExport.Name, subExport.Path, subExport.Pseudo)
+	return subExport, m.nfsServer.ReloadExport(ctx)
+}
+
+// DeleteSubExport unexports the sub-export and removes it from the volume, the directory is kept
+func (m *ShareManager) DeleteSubExport(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "ShareManager.DeleteSubExport", attribute.String("name", name))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	subExports, err := loadSubExports(m.volume.Name)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(subExports, func(subExport nfs.SubExport) bool { return subExport.Name == name })
+	if index < 0 {
+		return errors.Wrapf(ErrSubExportNotFound, "sub-export %v", name)
+	}
+
+	if err := m.nfsServer.DeleteSubExport(m.volume.Name, name); err != nil {
+		return err
+	}
+	if err := saveSubExports(m.volume.Name, slices.Delete(subExports, index, index+1)); err != nil {
+		return err
+	}
+	if err := m.releaseExportID(subExportIDKey(name)); err != nil {
+		m.logger.WithError(err).Warnf("Failed to release export id of sub-export %v", name)
+	}
+
+	m.logger.Infof("Deleted sub-export %v", name)
+	return m.nfsServer.ReloadExport(ctx)
+}
+
+// ExportSubExports adds the sub-exports persisted on the mounted volume and the exported snapshots
+// to the config, without reloading the nfs server. A sub-export which cannot be exported does not
+// fail the others.
+func (m *ShareManager) ExportSubExports(ctx context.Context) (err error) {
+	_, span := tracing.Start(ctx, "ShareManager.ExportSubExports")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	subExports, err := loadSubExports(m.volume.Name)
+	if err != nil {
+		return err
+	}
+
+	// the persisted sub-exports are validated again, the file may have been changed on the volume
+	var errs error
+	exported := []nfs.SubExport{}
+	for _, subExport := range subExports {
+		if slices.ContainsFunc(exported, func(other nfs.SubExport) bool { return other.Name == subExport.Name }) {
+			errs = errors.CombineErrors(errs, errors.Wrapf(ErrSubExportExists, "sub-export %v", subExport.Name))
+			continue
+		}
+		if subExport, err = m.normalizeSubExport(subExport, exported); err != nil {
+			errs = errors.CombineErrors(errs, errors.Wrapf(err, "sub-export %v", subExport.Name))
+			continue
+		}
+		if err := checkDirectoryInVolume(m.volume.Name, subExport.Path); err != nil {
+			errs = errors.CombineErrors(errs, err)
+			continue
+		}
+		exportID, err := m.claimExportID(subExportIDKey(subExport.Name))
+		if err != nil {
+			errs = errors.CombineErrors(errs, err)
+			continue
+		}
+		if _, err := m.nfsServer.CreateSubExport(m.volume.Name, subExport, exportID); err != nil {
+			errs = errors.CombineErrors(errs, err)
+			continue
+		}
+		exported = append(exported, subExport)
+	}
+	return errors.CombineErrors(errs, m.exportSnapshots())
+}
+
+// UnexportSubExports removes all sub-exports and snapshot exports from the config, the sub-exports
+// stay persisted on the volume and the snapshots stay mounted
+func (m *ShareManager) UnexportSubExports(ctx context.Context) (err error) {
+	_, span := tracing.Start(ctx, "ShareManager.UnexportSubExports")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	return m.nfsServer.DeleteSubExports(m.volume.Name)
+}
+
+// normalizeSubExport validates the sub-export against the existing ones and applies the defaults
+func (m *ShareManager) normalizeSubExport(subExport nfs.SubExport, existing []nfs.SubExport) (nfs.SubExport, error) {
+	if !subExportNameRegex.MatchString(subExport.Name) {
+		return subExport, errors.Wrapf(ErrInvalidSubExport, "name %q must consist of up to 63 letters, digits, '.', '_' or '-'", subExport.Name)
+	}
+
+	path, err := normalizeVolumePath(subExport.Path)
+	if err != nil {
+		return subExport, err
+	}
+	subExport.Path = path
+
+	// below the pseudo path of the volume export the clients see its directories, a sub-export
+	// there has to show the directory at the same place
+	volumePseudo := filepath.Join("/", m.volume.Name)
+	if subExport.Pseudo == "" {
+		subExport.Pseudo = filepath.Join(volumePseudo, subExport.Path)
+	}
+	subExport.Pseudo = filepath.Clean(subExport.Pseudo)
+	if !filepath.IsAbs(subExport.Pseudo) || subExport.Pseudo == "/" || subExport.Pseudo == volumePseudo {
+		return subExport, errors.Wrapf(ErrInvalidSubExport, "pseudo path %q must be absolute and differ from the volume export", subExport.Pseudo)
+	}
+	if rel, err := filepath.Rel(volumePseudo, subExport.Pseudo); err == nil && filepath.IsLocal(rel) && rel != subExport.Path {
+		return subExport, errors.Wrapf(ErrInvalidSubExport, "pseudo path %v below the volume export must be %v", subExport.Pseudo, filepath.Join(volumePseudo, subExport.Path))
+	}
+	if strings.ContainsAny(subExport.Pseudo, nfs.InvalidConfigChars) || strings.ContainsAny(subExport.Path, nfs.InvalidConfigChars) {
+		return subExport, errors.Wrapf(ErrInvalidSubExport, "path and pseudo path must not contain any of %q", nfs.InvalidConfigChars)
+	}
+	for _, other := range existing {
+		if other.Pseudo == subExport.Pseudo {
+			return subExport, errors.Wrapf(ErrInvalidSubExport, "pseudo path %v is already used by sub-export %v", subExport.Pseudo, other.Name)
+		}
+	}
+
+	if subExport.ExportOptions, err = subExport.ExportOptions.Normalize(); err != nil {
+		return subExport, errors.Wrap(ErrInvalidSubExport, err.Error())
+	}
+
+	return subExport, nil
+}
+
+func loadSubExports(volumeName string) ([]nfs.SubExport, error) {
+	path := filepath.Join(types.GetStatePath(volumeName), subExportsFile)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []nfs.SubExport{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read sub-exports from %v", path)
+	}
+
+	subExports := []nfs.SubExport{}
+	if err := json.Unmarshal(content, &subExports); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse sub-exports in %v", path)
+	}
+	return subExports, nil
+}
+
+func saveSubExports(volumeName string, subExports []nfs.SubExport) error {
+	stateDir := types.GetStatePath(volumeName)
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return errors.Wrapf(err, "failed to create state directory %v", stateDir)
+	}
+
+	content, err := json.MarshalIndent(subExports, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(stateDir, subExportsFile)
+	if err := util.WriteFileAtomic(path, content, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write sub-exports to %v", path)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/server/nfs"
+	"github.com/longhorn/longhorn-share-manager/pkg/volume"
+)
+
+func TestNormalizeSubExport(t *testing.T) {
+	m := &ShareManager{volume: volume.Volume{Name: "vol"}, logger: logrus.New()}
+	existing := []nfs.SubExport{{Name: "logs", Path: "logs", Pseudo: "/vol/logs"}}
+
+	tests := []struct {
+		name       string
+		subExport  nfs.SubExport
+		wantPseudo string
+		wantErr    bool
+	}{
+		{name: "default pseudo path", subExport: nfs.SubExport{Name: "data", Path: "data/set"}, wantPseudo: "/vol/data/set"},
+		{name: "pseudo path of the directory", subExport: nfs.SubExport{Name: "data", Path: "data", Pseudo: "/vol/data/"}, wantPseudo: "/vol/data"},
+		{name: "pseudo path out of the volume", subExport: nfs.SubExport{Name: "data", Path: "data", Pseudo: "/shared/data"}, wantPseudo: "/shared/data"},
+		{name: "pseudo path of another directory", subExport: nfs.SubExport{Name: "data", Path: "data", Pseudo: "/vol/other"}, wantErr: true},
+		{name: "pseudo path of the volume", subExport: nfs.SubExport{Name: "data", Path: "data", Pseudo: "/vol"}, wantErr: true},
+		{name: "root pseudo path", subExport: nfs.SubExport{Name: "data", Path: "data", Pseudo: "/"}, wantErr: true},
+		{name: "relative pseudo path", subExport: nfs.SubExport{Name: "data", Path: "data", Pseudo: "data"}, wantErr: true},
+		{name: "pseudo path in use", subExport: nfs.SubExport{Name: "data", Path: "data", Pseudo: "/vol/logs"}, wantErr: true},
+		{name: "invalid name", subExport: nfs.SubExport{Name: ".data", Path: "data"}, wantErr: true},
+		{name: "state directory", subExport: nfs.SubExport{Name: "state", Path: ".longhorn-share-manager"}, wantErr: true},
+		{name: "invalid character", subExport: nfs.SubExport{Name: "data", Path: "data;"}, wantErr: true},
+		{name: "invalid access type", subExport: nfs.SubExport{Name: "data", Path: "data", ExportOptions: nfs.ExportOptions{AccessType: "rw"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.normalizeSubExport(tt.subExport, existing)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeSubExport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Pseudo != tt.wantPseudo {
+				t.Errorf("normalizeSubExport() pseudo = %v, want %v", got.Pseudo, tt.wantPseudo)
+			}
+		})
+	}
+}
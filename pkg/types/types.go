@@ -18,12 +18,47 @@ const (
 	// The suffix for dm device name of the encrypted v2 volume.
 	MapperV2VolumeSuffix = "-encrypted"
 
+	// The suffix cryptsetup uses for the dm-integrity device below an authenticated encrypted volume.
+	MapperIntegritySuffix = "_dif"
+
 	ExportPath = "/export"
 
+	// StateMountRoot is where the volumes are mounted a second time, out of reach of the clients,
+	// to access the state directory hidden in the export
+	StateMountRoot = "/var/lib/longhorn-share-manager/volumes"
+
+	// StateDirName is the directory on the volume keeping the state of the share manager
+	// which has to survive restarts and failovers
+	StateDirName = ".longhorn-share-manager"
+
+	// RecoveryDirName is the directory in the state directory keeping the NFSv4 client
+	// recovery records of the fs recovery backend
+	RecoveryDirName = "recovery"
+
+	// SnapshotsDirName is the directory on the volume below which snapshots are mounted read-only
+	SnapshotsDirName = ".snapshots"
+
 	DataEngineTypeV1 = "v1"
 	DataEngineTypeV2 = "v2"
 )
 
+// Types and states of the filesystem operations running in the background of the share manager
+const (
+	OperationTypeTrim       = "trim"
+	OperationTypeResize     = "resize"
+	OperationTypeSetProject = "set-project"
+
+	OperationStateRunning   = "running"
+	OperationStateSucceeded = "succeeded"
+	OperationStateFailed    = "failed"
+	OperationStateCancelled = "cancelled"
+)
+
+// IsOperationDone returns true if the operation state is final.
+func IsOperationDone(state string) bool {
+	return state != OperationStateRunning
+}
+
 func GetEncryptVolumeName(volume, dataEngine string) string {
 	if dataEngine == DataEngineTypeV2 {
 		return volume + MapperV2VolumeSuffix
@@ -31,6 +66,10 @@ func GetEncryptVolumeName(volume, dataEngine string) string {
 	return volume
 }
 
+func GetIntegrityDeviceName(volume, dataEngine string) string {
+	return GetEncryptVolumeName(volume, dataEngine) + MapperIntegritySuffix
+}
+
 func GetVolumeDevicePath(volumeName, dataEngine string, EncryptedDevice bool) string {
 	if EncryptedDevice {
 		if dataEngine == DataEngineTypeV2 {
@@ -50,3 +89,23 @@ func GetRawVolumeDevicePath(volumeName string) string {
 func GetMountPath(volumeName string) string {
 	return filepath.Join(ExportPath, volumeName)
 }
+
+// GetStateMountPath returns the path of the private mount of the volume
+func GetStateMountPath(volumeName string) string {
+	return filepath.Join(StateMountRoot, volumeName)
+}
+
+// GetStatePath returns the state directory of the volume below its private mount, the one
+// in the export is hidden from the clients
+func GetStatePath(volumeName string) string {
+	return filepath.Join(GetStateMountPath(volumeName), StateDirName)
+}
+
+func GetRecoveryPath(volumeName string) string {
+	return filepath.Join(GetStatePath(volumeName), RecoveryDirName)
+}
+
+// GetSnapshotDeviceName returns the name of the crypto device of an encrypted snapshot of the volume
+func GetSnapshotDeviceName(volumeName, snapshotName string) string {
+	return volumeName + "-snapshot-" + snapshotName
+}
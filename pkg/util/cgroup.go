@@ -0,0 +1,76 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+
+	// cgroup v1 reports an unlimited memory limit as the largest page aligned int64
+	cgroupV1MemoryUnlimited = 1 << 62
+)
+
+// GetCgroupCPULimit returns the CPU limit of the container in CPUs, false if it is unlimited
+// or cannot be read. Both cgroup v2 and v1 are supported.
+func GetCgroupCPULimit() (float64, bool) {
+	if content, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu.max")); err == nil {
+		return parseCPUMax(string(content))
+	}
+
+	quota, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false
+	}
+	return parseCPUQuota(string(quota), string(period))
+}
+
+// GetCgroupMemoryLimit returns the memory limit of the container in bytes, false if it is
+// unlimited or cannot be read. Both cgroup v2 and v1 are supported.
+func GetCgroupMemoryLimit() (uint64, bool) {
+	content, err := os.ReadFile(filepath.Join(cgroupRoot, "memory.max"))
+	if err != nil {
+		if content, err = os.ReadFile(filepath.Join(cgroupRoot, "memory", "memory.limit_in_bytes")); err != nil {
+			return 0, false
+		}
+	}
+	return parseMemoryLimit(string(content))
+}
+
+// parseCPUMax parses the "$MAX $PERIOD" of the cgroup v2 cpu.max
+func parseCPUMax(content string) (float64, bool) {
+	fields := strings.Fields(content)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, false
+	}
+	return parseCPUQuota(fields[0], fields[1])
+}
+
+// parseCPUQuota parses the quota and period of cgroup v1, a quota of -1 is unlimited
+func parseCPUQuota(quota, period string) (float64, bool) {
+	quotaValue, err := strconv.ParseInt(strings.TrimSpace(quota), 10, 64)
+	if err != nil || quotaValue <= 0 {
+		return 0, false
+	}
+	periodValue, err := strconv.ParseInt(strings.TrimSpace(period), 10, 64)
+	if err != nil || periodValue <= 0 {
+		return 0, false
+	}
+	return float64(quotaValue) / float64(periodValue), true
+}
+
+// parseMemoryLimit parses the cgroup v2 memory.max or the cgroup v1 memory.limit_in_bytes
+func parseMemoryLimit(content string) (uint64, bool) {
+	limit, err := strconv.ParseUint(strings.TrimSpace(content), 10, 64)
+	if err != nil || limit >= cgroupV1MemoryUnlimited {
+		return 0, false
+	}
+	return limit, true
+}
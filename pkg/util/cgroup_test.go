@@ -0,0 +1,75 @@
+package util
+
+import "testing"
+
+func TestParseCPUMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    float64
+		wantOK  bool
+	}{
+		{name: "two CPUs", content: "200000 100000\n", want: 2, wantOK: true},
+		{name: "half a CPU", content: "50000 100000\n", want: 0.5, wantOK: true},
+		{name: "unlimited", content: "max 100000\n"},
+		{name: "missing period", content: "200000\n"},
+		{name: "invalid quota", content: "two 100000\n"},
+		{name: "zero period", content: "200000 0\n"},
+		{name: "empty", content: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCPUMax(tt.content)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseCPUMax(%q) = %v, %v, want %v, %v", tt.content, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseCPUQuota(t *testing.T) {
+	tests := []struct {
+		name   string
+		quota  string
+		period string
+		want   float64
+		wantOK bool
+	}{
+		{name: "four CPUs", quota: "400000\n", period: "100000\n", want: 4, wantOK: true},
+		{name: "unlimited", quota: "-1\n", period: "100000\n"},
+		{name: "invalid period", quota: "400000\n", period: "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCPUQuota(tt.quota, tt.period)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseCPUQuota(%q, %q) = %v, %v, want %v, %v", tt.quota, tt.period, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    uint64
+		wantOK  bool
+	}{
+		{name: "one GiB", content: "1073741824\n", want: 1 << 30, wantOK: true},
+		{name: "cgroup v2 unlimited", content: "max\n"},
+		{name: "cgroup v1 unlimited", content: "9223372036854771712\n"},
+		{name: "empty", content: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseMemoryLimit(tt.content)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseMemoryLimit(%q) = %v, %v, want %v, %v", tt.content, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
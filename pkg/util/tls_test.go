@@ -0,0 +1,156 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca *testCA) issue(t *testing.T, ip string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "share-manager"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP(ip)},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func handshake(t *testing.T, clientConfig *tls.Config, serverCert tls.Certificate) error {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{serverCert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func TestClientConfigReloadsCA(t *testing.T) {
+	oldCA := newTestCA(t, "old")
+	newCA := newTestCA(t, "new")
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(caFile, oldCA.pem, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reloader, err := NewCertificateReloader("", "", caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := reloader.ClientConfig("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherNameConfig, err := reloader.ClientConfig("127.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotate := func() {
+		if err := os.WriteFile(caFile, newCA.pem, 0600); err != nil {
+			t.Fatal(err)
+		}
+		future := time.Now().Add(time.Minute)
+		if err := os.Chtimes(caFile, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		config     *tls.Config
+		serverCert tls.Certificate
+		before     func()
+		wantErr    bool
+	}{
+		{name: "signed by the loaded CA", config: config, serverCert: oldCA.issue(t, "127.0.0.1")},
+		{name: "signed by another CA", config: config, serverCert: newCA.issue(t, "127.0.0.1"), wantErr: true},
+		{name: "name mismatch", config: otherNameConfig, serverCert: oldCA.issue(t, "127.0.0.1"), wantErr: true},
+		{name: "signed by the rotated CA", config: config, serverCert: newCA.issue(t, "127.0.0.1"), before: rotate},
+		{name: "signed by the replaced CA", config: config, serverCert: oldCA.issue(t, "127.0.0.1"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.before != nil {
+				tt.before()
+			}
+			if err := handshake(t, tt.config, tt.serverCert); (err != nil) != tt.wantErr {
+				t.Errorf("handshake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
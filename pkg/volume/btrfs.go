@@ -0,0 +1,58 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
+)
+
+const FsTypeBtrfs = "btrfs"
+
+// BtrfsSubvolumeMountOption returns the mount option mounting the subvolume instead of the top level
+func BtrfsSubvolumeMountOption(subvolume string) string {
+	return "subvol=" + subvolume
+}
+
+// ValidateBtrfsSubvolume checks that the subvolume is a path below the top level which can be
+// passed as a mount option, no subvolume mounts the top level
+func ValidateBtrfsSubvolume(subvolume string) error {
+	if subvolume == "" {
+		return nil
+	}
+	if !filepath.IsLocal(subvolume) || filepath.Clean(subvolume) != subvolume || subvolume == "." || strings.ContainsAny(subvolume, ",\n") {
+		return fmt.Errorf("invalid btrfs subvolume %q, expected a clean relative path without commas", subvolume)
+	}
+	return nil
+}
+
+// CreateBtrfsSubvolume creates the subvolume in the top level of the btrfs filesystem mounted at
+// mountPath, an existing subvolume is kept. Missing parent directories are created as directories.
+func CreateBtrfsSubvolume(ctx context.Context, mountPath, subvolume string) (created bool, err error) {
+	ctx, span := tracing.Start(ctx, "volume.CreateBtrfsSubvolume", attribute.String("subvolume", subvolume))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	path := filepath.Join(mountPath, subvolume)
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	if out, err := exec.CommandContext(ctx, "btrfs", "subvolume", "create", path).CombinedOutput(); err != nil {
+		return false, errors.Wrapf(err, "failed to create btrfs subvolume %v: %s", subvolume, strings.TrimSpace(string(out)))
+	}
+	return true, nil
+}
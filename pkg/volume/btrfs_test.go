@@ -0,0 +1,29 @@
+package volume
+
+import "testing"
+
+func TestValidateBtrfsSubvolume(t *testing.T) {
+	tests := []struct {
+		subvolume string
+		wantErr   bool
+	}{
+		{subvolume: ""},
+		{subvolume: "data"},
+		{subvolume: "volumes/pvc-1"},
+		{subvolume: "/data", wantErr: true},
+		{subvolume: "../data", wantErr: true},
+		{subvolume: "data/../other", wantErr: true},
+		{subvolume: "data/", wantErr: true},
+		{subvolume: ".", wantErr: true},
+		{subvolume: "data,ro", wantErr: true},
+		{subvolume: "data\nro", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subvolume, func(t *testing.T) {
+			if err := ValidateBtrfsSubvolume(tt.subvolume); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBtrfsSubvolume(%q) error = %v, wantErr %v", tt.subvolume, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,12 +1,23 @@
 package volume
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"strconv"
+	"strings"
 
+	"github.com/cockroachdb/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sys/unix"
 	"k8s.io/kubernetes/pkg/volume/util/hostutil"
 	"k8s.io/mount-utils"
 	utilexec "k8s.io/utils/exec"
+
+	lhns "github.com/longhorn/go-common-libs/ns"
+
+	"github.com/longhorn/longhorn-share-manager/pkg/tracing"
 )
 
 type Volume struct {
@@ -19,15 +30,76 @@ type Volume struct {
 	CryptoPBKDF                string
 	CryptoPBKDFForceIterations string
 	CryptoPBKDFMemory          string
+	CryptoIntegrity            string
+	CryptoHeaderPath           string
+	CryptoHeaderInit           bool
 	FsType                     string
 	MountOptions               []string
+	BtrfsSubvolume             string
+	RootPermissions            RootPermissions
+}
+
+const (
+	// RootPermissionsAlways sets the permissions of the root directory on every start
+	RootPermissionsAlways = "always"
+	// RootPermissionsPreserve only sets them on a freshly formatted filesystem
+	RootPermissionsPreserve = "preserve"
+
+	// DefaultRootMode is the mode the root directory always had before it was configurable
+	DefaultRootMode os.FileMode = 0777
+)
+
+// RootPermissions are set on the root directory of the filesystem after it is mounted
+type RootPermissions struct {
+	Mode os.FileMode
+	// UID and GID of -1 keep the current owner
+	UID int
+	GID int
+	// Setgid lets new files inherit the group of the root directory, like the fsGroup of a pod
+	Setgid bool
+	Policy string
+}
+
+// ParseRootMode parses the permission bits of an octal mode, the setgid bit has its own flag
+func ParseRootMode(mode string) (os.FileMode, error) {
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || value > 0777 {
+		return 0, fmt.Errorf("invalid root mode %q, expected octal permission bits like 0755", mode)
+	}
+	return os.FileMode(value), nil
+}
+
+// ValidateRootPermissionsPolicy checks that the policy is supported
+func ValidateRootPermissionsPolicy(policy string) error {
+	if policy != RootPermissionsAlways && policy != RootPermissionsPreserve {
+		return fmt.Errorf("unknown root permissions policy %q, expected %v or %v",
+			policy, RootPermissionsAlways, RootPermissionsPreserve)
+	}
+	return nil
 }
 
 func (v Volume) IsEncrypted() bool {
 	return len(v.Passphrase) > 0
 }
 
-func GetDiskFormat(devicePath string) (string, error) {
+// LuksFormatOptions returns the options used for the initial LUKS format of the volume
+func (v Volume) LuksFormatOptions() *lhns.LuksFormatOptions {
+	return &lhns.LuksFormatOptions{
+		KeyCipher:            v.CryptoKeyCipher,
+		KeyHash:              v.CryptoKeyHash,
+		KeySize:              v.CryptoKeySize,
+		PBKDF:                v.CryptoPBKDF,
+		PBKDFForceIterations: v.CryptoPBKDFForceIterations,
+		PBKDFMemory:          v.CryptoPBKDFMemory,
+	}
+}
+
+func GetDiskFormat(ctx context.Context, devicePath string) (format string, err error) {
+	_, span := tracing.Start(ctx, "volume.GetDiskFormat", attribute.String("devicePath", devicePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	mounter := &mount.SafeFormatAndMount{Interface: mount.New(""), Exec: utilexec.New()}
 	return mounter.GetDiskFormat(devicePath)
 }
@@ -42,7 +114,13 @@ func CheckMountValid(mountPath string) bool {
 	return err == nil && isMountPoint
 }
 
-func MountVolume(devicePath, mountPath, fsType string, mountOptions []string, formatOptions []string) error {
+func MountVolume(ctx context.Context, devicePath, mountPath, fsType string, mountOptions []string, formatOptions []string) (err error) {
+	_, span := tracing.Start(ctx, "volume.MountVolume",
+		attribute.String("devicePath", devicePath), attribute.String("mountPath", mountPath), attribute.String("fsType", fsType))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	if !CheckDeviceValid(devicePath) {
 		return fmt.Errorf("cannot mount device %v to %v invalid device", devicePath, mountPath)
 	}
@@ -67,7 +145,80 @@ func MountVolume(devicePath, mountPath, fsType string, mountOptions []string, fo
 		mountOptions, nil, formatOptions)
 }
 
-func ResizeVolume(devicePath, mountPath string) (bool, error) {
+// MountReadOnly mounts a snapshot of a filesystem read-only on a directory opened by the caller,
+// so the mount point cannot be swapped for a symlink between opening and mounting it. The journal
+// is not replayed, since that would write to the device, and xfs has to ignore that the live
+// volume has the same uuid.
+func MountReadOnly(ctx context.Context, devicePath string, mountPoint *os.File, fsType string) (err error) {
+	_, span := tracing.Start(ctx, "volume.MountReadOnly", attribute.String("devicePath", devicePath), attribute.String("mountPoint", mountPoint.Name()))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	var options []string
+	switch fsType {
+	case "ext3", "ext4":
+		options = append(options, "noload")
+	case "xfs":
+		options = append(options, "norecovery", "nouuid")
+	case FsTypeBtrfs:
+		options = append(options, "rescue=nologreplay")
+	}
+
+	// the mount syscall resolves the magic link to the opened directory itself
+	target := fmt.Sprintf("/proc/self/fd/%d", mountPoint.Fd())
+	if err := unix.Mount(devicePath, target, fsType, unix.MS_RDONLY|unix.MS_NOSUID|unix.MS_NODEV, strings.Join(options, ",")); err != nil {
+		return errors.Wrapf(err, "failed to mount %v on %v", devicePath, mountPoint.Name())
+	}
+	return nil
+}
+
+// BindMountPrivate makes the filesystem mounted at the source path also available at the mount
+// path. The bind mount does not propagate, so mounts over directories below the source path
+// are not visible through it.
+func BindMountPrivate(ctx context.Context, sourcePath, mountPath string) (err error) {
+	_, span := tracing.Start(ctx, "volume.BindMountPrivate", attribute.String("sourcePath", sourcePath), attribute.String("mountPath", mountPath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if CheckMountValid(mountPath) {
+		return nil
+	}
+
+	if err := makeDir(mountPath); err != nil {
+		return err
+	}
+	if err := mount.New("").Mount(sourcePath, mountPath, "", []string{"bind"}); err != nil {
+		return err
+	}
+	if out, err := exec.CommandContext(ctx, "mount", "--make-private", mountPath).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to make mount %v private: %s", mountPath, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// HideDirectory mounts an empty read-only filesystem over the directory, so the content is not
+// reachable through this mount
+func HideDirectory(ctx context.Context, path string) (err error) {
+	_, span := tracing.Start(ctx, "volume.HideDirectory", attribute.String("path", path))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if CheckMountValid(path) {
+		return nil
+	}
+
+	return mount.New("").Mount("tmpfs", path, "tmpfs", []string{"ro", "nosuid", "nodev", "noexec", "size=4k", "mode=0500"})
+}
+
+func ResizeVolume(ctx context.Context, devicePath, mountPath string) (resized bool, err error) {
+	_, span := tracing.Start(ctx, "volume.ResizeVolume", attribute.String("devicePath", devicePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	// check if we need to resize the fs
 	// this is important since cloned volumes of bigger size don't trigger NodeExpandVolume
 	// therefore NodeExpandVolume is kind of redundant since we have to do this anyway
@@ -84,15 +235,49 @@ func ResizeVolume(devicePath, mountPath string) (bool, error) {
 	return false, nil
 }
 
-func SetPermissions(mountPath string, mode os.FileMode) error {
+// EnableExt4ProjectQuota turns on the project quota feature of an unmounted ext4 filesystem
+func EnableExt4ProjectQuota(ctx context.Context, devicePath string) (err error) {
+	ctx, span := tracing.Start(ctx, "volume.EnableExt4ProjectQuota", attribute.String("devicePath", devicePath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if out, err := exec.CommandContext(ctx, "tune2fs", "-O", "quota,project", "-Q", "prjquota", devicePath).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to enable project quota on %v: %s", devicePath, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SetRootPermissions sets the owner and mode of the root directory of the mounted filesystem
+func SetRootPermissions(mountPath string, permissions RootPermissions) error {
 	if !CheckMountValid(mountPath) {
-		return fmt.Errorf("cannot set permissions %v for path %v invalid mount point", mode, mountPath)
+		return fmt.Errorf("cannot set permissions %v for path %v invalid mount point", permissions.Mode, mountPath)
+	}
+
+	return applyRootPermissions(mountPath, permissions)
+}
+
+// applyRootPermissions changes the owner first, since chown clears the setgid bit
+func applyRootPermissions(path string, permissions RootPermissions) error {
+	mode := permissions.Mode
+	if permissions.Setgid {
+		mode |= os.ModeSetgid
 	}
 
-	return os.Chmod(mountPath, mode)
+	if permissions.UID >= 0 || permissions.GID >= 0 {
+		if err := os.Chown(path, permissions.UID, permissions.GID); err != nil {
+			return err
+		}
+	}
+	return os.Chmod(path, mode)
 }
 
-func UnmountVolume(mountPath string) error {
+func UnmountVolume(ctx context.Context, mountPath string) (err error) {
+	_, span := tracing.Start(ctx, "volume.UnmountVolume", attribute.String("mountPath", mountPath))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	mounter := mount.New("")
 	return mounter.Unmount(mountPath)
 }
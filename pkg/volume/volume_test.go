@@ -0,0 +1,101 @@
+package volume
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRootMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    os.FileMode
+		wantErr bool
+	}{
+		{mode: "0777", want: 0777},
+		{mode: "0755", want: 0755},
+		{mode: "770", want: 0770},
+		{mode: "0", want: 0},
+		{mode: "02775", wantErr: true},
+		{mode: "0888", wantErr: true},
+		{mode: "rwxr-xr-x", wantErr: true},
+		{mode: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, err := ParseRootMode(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRootMode(%q) error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRootMode(%q) = %v, want %v", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRootPermissionsPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr bool
+	}{
+		{policy: RootPermissionsAlways},
+		{policy: RootPermissionsPreserve},
+		{policy: "never", wantErr: true},
+		{policy: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			if err := ValidateRootPermissionsPolicy(tt.policy); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRootPermissionsPolicy(%q) error = %v, wantErr %v", tt.policy, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyRootPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions RootPermissions
+		want        os.FileMode
+	}{
+		{
+			name:        "default mode keeps the owner",
+			permissions: RootPermissions{Mode: DefaultRootMode, UID: -1, GID: -1},
+			want:        os.ModeDir | 0777,
+		},
+		{
+			name:        "restricted mode",
+			permissions: RootPermissions{Mode: 0750, UID: -1, GID: -1},
+			want:        os.ModeDir | 0750,
+		},
+		{
+			name:        "setgid survives the chown",
+			permissions: RootPermissions{Mode: 0775, UID: os.Getuid(), GID: os.Getgid(), Setgid: true},
+			want:        os.ModeDir | os.ModeSetgid | 0775,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "root")
+			if err := os.Mkdir(path, 0700); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := applyRootPermissions(path, tt.permissions); err != nil {
+				t.Fatalf("applyRootPermissions() error = %v", err)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode() != tt.want {
+				t.Errorf("applyRootPermissions() mode = %v, want %v", info.Mode(), tt.want)
+			}
+		})
+	}
+}